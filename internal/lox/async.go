@@ -0,0 +1,204 @@
+package lox
+
+import "fmt"
+
+type promiseState int
+
+const (
+	pending promiseState = iota
+	fulfilled
+	rejected
+)
+
+var promiseStateNames = map[promiseState]string{
+	pending:   "pending",
+	fulfilled: "fulfilled",
+	rejected:  "rejected",
+}
+
+// LoxPromise is the eventual result of an async function or timer.
+type LoxPromise struct {
+	state promiseState
+	value interface{}
+	err   *RuntimeError
+	// callbacks run on the event loop once the promise settles.
+	callbacks []func()
+	// handled is set once something awaits the promise or attaches a handler,
+	// so rejections nobody observes can be reported.
+	handled bool
+}
+
+func (p *LoxPromise) toString() string {
+	return fmt.Sprintf("<promise %v>", promiseStateNames[p.state])
+}
+
+func (p *LoxPromise) get(name Token) (interface{}, *RuntimeError) {
+	switch name.Lexeme {
+	case "then":
+		return &NativeFunction{"then", 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+			return p.then(arguments[0], nil), nil
+		}}, nil
+	case "catch":
+		return &NativeFunction{"catch", 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+			return p.then(nil, arguments[0]), nil
+		}}, nil
+	}
+
+	return nil, &RuntimeError{Token: name, Message: fmt.Sprintf("Undefined property '%v'.", name.Lexeme)}
+}
+
+// then returns a promise for the result of calling onFulfilled with the value,
+// or onRejected with the error message. A nil handler passes the outcome on.
+func (p *LoxPromise) then(onFulfilled interface{}, onRejected interface{}) *LoxPromise {
+	next := &LoxPromise{}
+	p.handled = true
+	p.onSettle(func() {
+		handler, argument := onFulfilled, p.value
+		if p.state == rejected {
+			handler, argument = onRejected, p.err.Message
+		}
+		if handler == nil {
+			next.settle(p.value, p.err)
+			return
+		}
+		next.settle(callValue(handler, Token{}, []*Token{nil}, []interface{}{argument}))
+	})
+	return next
+}
+
+func (p *LoxPromise) onSettle(callback func()) {
+	if p.state == pending {
+		p.callbacks = append(p.callbacks, callback)
+		return
+	}
+	interpreter.loop.enqueue(callback)
+}
+
+// settle fulfills the promise with value, or rejects it if err is set. A
+// promise value is adopted, so the promise settles the same way it does.
+func (p *LoxPromise) settle(value interface{}, err *RuntimeError) {
+	if p.state != pending {
+		return
+	}
+	if inner, ok := value.(*LoxPromise); ok && err == nil {
+		inner.handled = true
+		inner.onSettle(func() { p.settle(inner.value, inner.err) })
+		return
+	}
+
+	if err != nil {
+		p.state, p.err = rejected, err
+		interpreter.loop.rejections = append(interpreter.loop.rejections, p)
+	} else {
+		p.state, p.value = fulfilled, value
+	}
+	for _, callback := range p.callbacks {
+		interpreter.loop.enqueue(callback)
+	}
+	p.callbacks = nil
+}
+
+// coroutine runs the body of an async function on its own goroutine so that it
+// can be suspended at an await. Only one coroutine runs at a time: control is
+// handed back and forth over the resume and yield channels.
+type coroutine struct {
+	resume chan struct{}
+	yield  chan struct{}
+	frames []frame
+	// cancelled is set when the coroutine is resumed only to unwind, because
+	// the promise it awaits can never settle.
+	cancelled bool
+}
+
+// runAsync starts body as a coroutine and returns a promise for its result.
+// The body runs synchronously until it first awaits.
+func runAsync(body func() (interface{}, *RuntimeError)) *LoxPromise {
+	promise := &LoxPromise{}
+	co := &coroutine{
+		resume: make(chan struct{}),
+		yield:  make(chan struct{}),
+		frames: append([]frame{}, interpreter.frames...),
+	}
+
+	go func() {
+		<-co.resume
+		value, err := body()
+		if err != nil && err.trace == nil {
+			annotated := *err
+			annotated.trace = interpreter.stackTrace(&annotated)
+			err = &annotated
+		}
+		if !co.cancelled {
+			promise.settle(value, err)
+		}
+		co.yield <- struct{}{}
+	}()
+
+	co.switchTo()
+	return promise
+}
+
+// switchTo runs the coroutine until it suspends or finishes.
+func (co *coroutine) switchTo() {
+	previous, environment, frames := interpreter.coroutine, interpreter.environment, interpreter.frames
+	interpreter.coroutine, interpreter.frames = co, co.frames
+
+	co.resume <- struct{}{}
+	<-co.yield
+
+	co.frames = interpreter.frames
+	interpreter.coroutine, interpreter.environment, interpreter.frames = previous, environment, frames
+}
+
+// suspend hands control back to whoever resumed the coroutine, and returns
+// once it is resumed again.
+func (co *coroutine) suspend() {
+	environment := interpreter.environment
+	interpreter.loop.suspended[co] = true
+	co.yield <- struct{}{}
+	<-co.resume
+	delete(interpreter.loop.suspended, co)
+	interpreter.environment = environment
+}
+
+// cancel resumes a suspended coroutine so that its await fails, and returns
+// once the body has unwound and the goroutine is done.
+func (co *coroutine) cancel() {
+	co.cancelled = true
+	co.switchTo()
+}
+
+func (a *Await) Eval() (interface{}, *RuntimeError) {
+	value, err := a.value.Eval()
+	if err != nil {
+		return nil, err
+	}
+	promise, ok := value.(*LoxPromise)
+	if !ok {
+		return value, nil
+	}
+
+	promise.handled = true
+	if promise.state == pending {
+		if co := interpreter.coroutine; co != nil {
+			promise.onSettle(co.switchTo)
+			co.suspend()
+			if co.cancelled {
+				return nil, &RuntimeError{Token: a.keyword, Message: "Awaited promise can never settle."}
+			}
+		} else if !interpreter.loop.runUntil(func() bool { return promise.state != pending }) {
+			// At the top level there is nothing to suspend, so run the event
+			// loop until the promise settles.
+			return nil, &RuntimeError{Token: a.keyword, Message: "Awaited promise can never settle."}
+		}
+	}
+	if interpreter.exited {
+		// The loop stopped because a timer or a task called exit().
+		return nil, exitError()
+	}
+
+	if promise.state == rejected {
+		return nil, promise.err
+	}
+	return promise.value, nil
+}
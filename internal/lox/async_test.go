@@ -0,0 +1,82 @@
+package lox
+
+import (
+	"bytes"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAsync(t *testing.T) {
+	checkScripts(t, []scriptTest{
+		{name: "await", source: `
+async fun double(x) { await sleep(10); return x * 2; }
+async fun main() { print "start"; print await double(21); }
+main();
+print "sync";`, want: "start\nsync\n42\n"},
+		{name: "top-level await", source: `await sleep(1); print "after";`, want: "after\n"},
+		{name: "then and catch", source: `
+async fun ok() { return 1; }
+async fun bad() { return nil.x; }
+ok().then((v) => { print v; });
+bad().catch((e) => { print "caught " + e; });`, want: "1\ncaught Only instances have properties.\n"},
+		{name: "unhandled rejection", source: `
+async fun bad() { return nil.x; }
+bad();
+print "end";`, want: "end\n", err: "Unhandled promise rejection: Only instances have properties."},
+		{name: "await outside async", source: `fun f() { await sleep(1); }`,
+			err: "Can only use 'await' inside an async function."},
+	})
+}
+
+func TestUnhandledRejectionStatus(t *testing.T) {
+	_, stderr, status := runScript(t, `async fun bad() { return nil.x; } bad();`)
+	if !strings.Contains(stderr, "Unhandled promise rejection") || status != 70 {
+		t.Errorf("got error output %q and status %d, want an unhandled rejection and status 70", stderr, status)
+	}
+}
+
+func TestSuspendedCoroutinesFinish(t *testing.T) {
+	previous := interpreter
+	interpreter = NewInterpreter()
+	defer func() { interpreter = previous }()
+	var stderr bytes.Buffer
+	interpreter.SetOutput(io.Discard, &stderr)
+
+	// f ends up awaiting its own promise, which can never settle.
+	before := runtime.NumGoroutine()
+	interpreter.lock.Lock()
+	run(&Scanner{Source: `var p; async fun f() { await sleep(0); await p; } p = f();`, Line: 1})
+	interpreter.loop.run()
+	interpreter.lock.Unlock()
+
+	for deadline := time.Now().Add(time.Second); runtime.NumGoroutine() > before; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d goroutines after the loop finished, want %d", runtime.NumGoroutine(), before)
+		}
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("got error output %q, want none", stderr.String())
+	}
+}
+
+func TestTimers(t *testing.T) {
+	checkScripts(t, []scriptTest{
+		{name: "order", source: `
+setTimeout(() => { print "late"; }, 30);
+var cancelled = setTimeout(() => { print "never"; }, 10);
+clearTimeout(cancelled);
+var n = 0;
+var id = setInterval(() => {
+  n = n + 1;
+  print n;
+  if (n == 2) clearInterval(id);
+}, 10);
+print "sync";`, want: "sync\n1\n2\nlate\n"},
+		{name: "error in callback", source: `
+setTimeout(() => { print nil.x; }, 1);
+setTimeout(() => { print "next"; }, 2);`, want: "next\n", err: "Only instances have properties."},
+	})
+}
@@ -1,29 +1,78 @@
 package lox
 
-import "fmt"
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
 
 type Callable interface {
-	call([]interface{}) interface{}
-	arity() int
+	call([]interface{}) (interface{}, *RuntimeError)
+	// arity returns the minimum and maximum number of arguments accepted.
+	arity() (int, int)
 	toString() string
 }
 
+// NamedCallable is implemented by callables whose parameters can be passed by name.
+type NamedCallable interface {
+	Callable
+	parameters() []string
+}
+
+// missingArgument fills the slot of an optional parameter that was skipped
+// over by a named argument, so the callee falls back to its default.
+type missingArgument struct{}
+
+var missing = missingArgument{}
+
 type LoxFunction struct {
 	declaration   *Function
 	closure       *Environment
 	isInitializer bool
+	// decorators are kept on methods and applied after binding, so they wrap
+	// a method that already knows its 'this'.
+	decorators []interface{}
 }
 
-func (lf *LoxFunction) bind(instance *LoxInstance) *LoxFunction {
+// bind returns a copy of the method with 'this' bound to instance, which is
+// either a *LoxInstance or a *LoxEnumValue.
+func (lf *LoxFunction) bind(instance interface{}) *LoxFunction {
 	env := NewEnvironmentWithEnclosing(lf.closure)
 	env.define("this", instance)
 	return &LoxFunction{declaration: lf.declaration, closure: env, isInitializer: lf.isInitializer}
 }
-func (lf *LoxFunction) call(arguments []interface{}) (out interface{}) {
+func (lf *LoxFunction) call(arguments []interface{}) (interface{}, *RuntimeError) {
+	if lf.declaration.async {
+		return runAsync(func() (interface{}, *RuntimeError) {
+			return lf.invoke(arguments)
+		}), nil
+	}
+	return lf.invoke(arguments)
+}
+
+// invoke runs the function body with the given arguments bound to its parameters.
+func (lf *LoxFunction) invoke(arguments []interface{}) (out interface{}, err *RuntimeError) {
 	environment := NewEnvironmentWithEnclosing(lf.closure)
 
-	for i := 0; i < len(lf.declaration.params); i++ {
-		environment.define(lf.declaration.params[i].Lexeme, arguments[i])
+	for i, param := range lf.declaration.params {
+		var value interface{}
+		if i < len(arguments) && arguments[i] != missing {
+			value = arguments[i]
+		} else if i < len(lf.declaration.defaults) && lf.declaration.defaults[i] != nil {
+			// Defaults are evaluated on every call, in the function's closure.
+			value, err = evaluateIn(lf.closure, lf.declaration.defaults[i])
+			if err != nil {
+				return nil, err
+			}
+		}
+		environment.define(param.Lexeme, value)
+	}
+	if rest := lf.declaration.rest; rest != nil {
+		var extra []interface{}
+		if len(arguments) > len(lf.declaration.params) {
+			extra = append(extra, arguments[len(lf.declaration.params):]...)
+		}
+		environment.define(rest.Lexeme, NewList(extra))
 	}
 
 	// Try to execute the function block and catch the return value if it occurs.
@@ -41,20 +90,119 @@ func (lf *LoxFunction) call(arguments []interface{}) (out interface{}) {
 		}
 	}()
 
-	executeBlock(lf.declaration.body, environment)
+	err = executeBlock(lf.declaration.body, environment)
+	if err != nil {
+		return nil, err
+	}
 	if lf.isInitializer {
-		value, _ := lf.closure.getAt(0, "this")
-		return value
+		return lf.closure.getAt(0, "this")
 	}
-	return nil
+	return nil, nil
 }
 
-func (lf *LoxFunction) arity() int {
-	return len(lf.declaration.params)
+func (lf *LoxFunction) arity() (int, int) {
+	required := 0
+	for i := range lf.declaration.params {
+		if i >= len(lf.declaration.defaults) || lf.declaration.defaults[i] == nil {
+			required++
+		}
+	}
+	if lf.declaration.rest != nil {
+		return required, variadic
+	}
+	return required, len(lf.declaration.params)
+}
+
+func (lf *LoxFunction) parameters() []string {
+	var names []string
+	for _, param := range lf.declaration.params {
+		names = append(names, param.Lexeme)
+	}
+	return names
 }
 
 func (lf *LoxFunction) toString() string {
-	return fmt.Sprintf("<fn %v>", lf.declaration.name.Lexeme)
+	name := lf.declaration.name
+	if name.Type != IDENTIFIER {
+		// Anonymous functions are named by the token that introduced them.
+		return fmt.Sprintf("<fn anonymous@%v>", name.Line)
+	}
+	return fmt.Sprintf("<fn %v>", name.Lexeme)
+}
+
+// bindArguments lines up the positional and named arguments of a call with the
+// parameters of the callee, and checks the result against its arity.
+func bindArguments(function Callable, paren Token, names []*Token, values []interface{}) ([]interface{}, *RuntimeError) {
+	var params []string
+	if named, ok := function.(NamedCallable); ok {
+		params = named.parameters()
+	}
+
+	var arguments []interface{}
+	var unknown []string
+	for i, value := range values {
+		if names[i] == nil {
+			arguments = append(arguments, value)
+			continue
+		}
+		if params == nil {
+			return nil, &RuntimeError{Token: *names[i], Message: fmt.Sprintf("%v doesn't accept named arguments.", function.toString())}
+		}
+
+		index := slices.Index(params, names[i].Lexeme)
+		if index < 0 {
+			unknown = append(unknown, names[i].Lexeme)
+			continue
+		}
+		for len(arguments) <= index {
+			arguments = append(arguments, missing)
+		}
+		if arguments[index] != missing {
+			return nil, &RuntimeError{Token: *names[i], Message: fmt.Sprintf("Got multiple values for parameter '%v'.", names[i].Lexeme)}
+		}
+		arguments[index] = value
+	}
+	if len(unknown) > 0 {
+		return nil, &RuntimeError{Token: paren, Message: fmt.Sprintf("Unknown %v %v.", plural("parameter", len(unknown)), quoteAll(unknown))}
+	}
+
+	minArity, maxArity := function.arity()
+	if maxArity != variadic && len(arguments) > maxArity {
+		if minArity == maxArity {
+			return nil, &RuntimeError{Token: paren, Message: fmt.Sprintf("Expected %v arguments but got %v.", maxArity, len(arguments))}
+		}
+		return nil, &RuntimeError{Token: paren, Message: fmt.Sprintf("Expected at most %v arguments but got %v.", maxArity, len(arguments))}
+	}
+
+	var absent []string
+	for i := 0; i < minArity; i++ {
+		if i >= len(arguments) || arguments[i] == missing {
+			if params == nil {
+				return nil, &RuntimeError{Token: paren, Message: fmt.Sprintf("Expected %v arguments but got %v.", minArity, len(arguments))}
+			}
+			absent = append(absent, params[i])
+		}
+	}
+	if len(absent) > 0 {
+		return nil, &RuntimeError{Token: paren, Message: fmt.Sprintf("Missing %v for %v %v.",
+			plural("argument", len(absent)), plural("parameter", len(absent)), quoteAll(absent))}
+	}
+	return arguments, nil
+}
+
+func plural(word string, count int) string {
+	if count == 1 {
+		return word
+	}
+	return word + "s"
+}
+
+func quoteAll(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = "'" + name + "'"
+	}
+	return strings.Join(quoted, ", ")
 }
 
 // Return is a custom error type used to signal a function return.
@@ -0,0 +1,73 @@
+package lox
+
+import "testing"
+
+func TestDefaultAndNamedArguments(t *testing.T) {
+	checkScripts(t, []scriptTest{
+		{name: "defaults", source: `
+fun greet(name, greeting = "hi") { print greeting + " " + name; }
+greet("al");
+greet("al", "yo");`, want: "hi al\nyo al\n"},
+		{name: "evaluated per call", source: `
+var n = 0;
+fun next() { n = n + 1; return n; }
+fun f(x = next()) { print x; }
+f(); f(); f(10);`, want: "1\n2\n10\n"},
+		{name: "named", source: `
+fun greet(name, greeting = "hi") { print greeting + " " + name; }
+greet(greeting: "yo", name: "al");
+greet("al", greeting: "hey");`, want: "yo al\nhey al\n"},
+		{name: "skipped default", source: `
+fun f(a, b = 2, c = 3) { print a + b + c; }
+f(1, c: 10);`, want: "13\n"},
+		{name: "missing", source: `fun f(a, b) {} f(b: 2);`,
+			err: "Missing argument for parameter 'a'."},
+		{name: "unknown", source: `fun f(a) {} f(1, d: 2, e: 3);`,
+			err: "Unknown parameters 'd', 'e'."},
+		{name: "duplicate", source: `fun f(a) {} f(1, a: 2);`,
+			err: "Got multiple values for parameter 'a'."},
+		{name: "too many", source: `fun f(a, b = 1) {} f(1, 2, 3);`,
+			err: "Expected at most 2 arguments but got 3."},
+		{name: "positional after named", source: `fun f(a, b) {} f(a: 1, 2);`,
+			err: "Positional argument can't follow a named argument."},
+		{name: "required after default", source: `fun f(a = 1, b) {}`,
+			err: "A parameter without a default can't follow one with a default."},
+	})
+}
+
+func TestVariadics(t *testing.T) {
+	checkScripts(t, []scriptTest{
+		{name: "rest", source: `
+fun f(a, ...rest) { print a; print rest; }
+f(1);
+f(1, 2, 3);`, want: "1\n[]\n1\n[2, 3]\n"},
+		{name: "spread", source: `
+fun show(...xs) { print xs; }
+var l = List(4, 5);
+show(1, ...l, 6);
+show(...List());`, want: "[1, 4, 5, 6]\n[]\n"},
+		{name: "spread non-list", source: `fun f(...a) {} f(...1);`,
+			err: "Can only spread lists."},
+		{name: "rest not last", source: `fun f(...a, b) {}`,
+			err: "A rest parameter must be the last parameter."},
+	})
+}
+
+func TestCallValueCopiesErrors(t *testing.T) {
+	// A native function may return the same error on every call.
+	shared := &RuntimeError{Message: "boom"}
+	native := &NativeFunction{"fail", 0, 0, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		return nil, shared
+	}}
+
+	for _, line := range []int{3, 7} {
+		paren := Token{Type: RIGHT_PAREN, Lexeme: ")", Line: line}
+		_, err := callValue(native, paren, nil, nil)
+		if err == nil || err.Message != "boom" || err.Token.Line != line {
+			t.Errorf("got %+v, want boom at line %d", err, line)
+		}
+	}
+	if shared.Token.Line != 0 || shared.trace != nil {
+		t.Errorf("callValue annotated the callee's error: %+v", shared)
+	}
+}
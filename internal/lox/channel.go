@@ -0,0 +1,135 @@
+package lox
+
+import "fmt"
+
+// LoxChannel passes values between tasks. Sends block while the buffer is
+// full; on an unbuffered channel they block until the value is received.
+type LoxChannel struct {
+	capacity int
+	buffer   []interface{}
+	closed   bool
+	// sent and received count values in and out, so a sender on an unbuffered
+	// channel can tell when its value has been taken.
+	sent     int
+	received int
+}
+
+func (c *LoxChannel) toString() string {
+	return fmt.Sprintf("<channel %d/%d>", len(c.buffer), c.capacity)
+}
+
+func (c *LoxChannel) get(name Token) (interface{}, *RuntimeError) {
+	switch name.Lexeme {
+	case "send":
+		return &NativeFunction{"send", 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+			return nil, c.send(arguments[0])
+		}}, nil
+	case "receive":
+		return &NativeFunction{"receive", 0, 0, func(arguments []interface{}) (interface{}, *RuntimeError) {
+			return c.receive()
+		}}, nil
+	case "close":
+		return &NativeFunction{"close", 0, 0, func(arguments []interface{}) (interface{}, *RuntimeError) {
+			if c.closed {
+				return nil, &RuntimeError{Message: "Channel is already closed."}
+			}
+			c.closed = true
+			interpreter.notify()
+			return nil, nil
+		}}, nil
+	case "closed":
+		return c.closed, nil
+	}
+
+	return nil, &RuntimeError{Token: name, Message: fmt.Sprintf("Undefined property '%v'.", name.Lexeme)}
+}
+
+func (c *LoxChannel) send(value interface{}) *RuntimeError {
+	for !c.closed && len(c.buffer) >= max(c.capacity, 1) {
+		if err := interpreter.wait(); err != nil {
+			return err
+		}
+	}
+	if c.closed {
+		return &RuntimeError{Message: "Send on closed channel."}
+	}
+
+	c.buffer = append(c.buffer, value)
+	c.sent++
+	interpreter.notify()
+
+	if c.capacity == 0 {
+		for seq := c.sent; c.received < seq; {
+			if err := interpreter.wait(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// receive takes the next value, blocking until there is one. Once the channel
+// is closed and drained it returns nil.
+func (c *LoxChannel) receive() (interface{}, *RuntimeError) {
+	for !c.ready() {
+		if err := interpreter.wait(); err != nil {
+			return nil, err
+		}
+	}
+	return c.take(), nil
+}
+
+// ready reports whether receive would return without blocking.
+func (c *LoxChannel) ready() bool {
+	return len(c.buffer) > 0 || c.closed
+}
+
+func (c *LoxChannel) take() interface{} {
+	if len(c.buffer) == 0 {
+		return nil
+	}
+	value := c.buffer[0]
+	c.buffer = c.buffer[1:]
+	c.received++
+	interpreter.notify()
+	return value
+}
+
+// defineChannelNatives registers the Channel constructor and select.
+func defineChannelNatives(i *Interpreter) {
+	i.globals.define("Channel", &NativeFunction{"Channel", 0, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		capacity := 0.0
+		if len(arguments) == 1 {
+			number, ok := arguments[0].(float64)
+			if !ok || number < 0 || number != float64(int(number)) {
+				return nil, &RuntimeError{Message: "Channel capacity must be a non-negative integer."}
+			}
+			capacity = number
+		}
+		return &LoxChannel{capacity: int(capacity)}, nil
+	}})
+
+	// select waits until one of the channels can be received from, and returns
+	// a list of that channel and the value received.
+	i.globals.define("select", &NativeFunction{"select", 1, variadic, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		var channels []*LoxChannel
+		for _, argument := range arguments {
+			channel, ok := argument.(*LoxChannel)
+			if !ok {
+				return nil, &RuntimeError{Message: "Can only select on channels."}
+			}
+			channels = append(channels, channel)
+		}
+
+		for {
+			for _, channel := range channels {
+				if channel.ready() {
+					return NewList([]interface{}{channel, channel.take()}), nil
+				}
+			}
+			if err := i.wait(); err != nil {
+				return nil, err
+			}
+		}
+	}})
+}
@@ -3,31 +3,43 @@ package lox
 type LoxClass struct {
 	name       string
 	methods    map[string]*LoxFunction
+	getters    map[string]*LoxFunction
+	setters    map[string]*LoxFunction
 	superclass *LoxClass
 }
 
-func (lc LoxClass) toString() string {
-	return lc.name
+func (lc *LoxClass) toString() string {
+	return "<class " + lc.name + ">"
 }
 
-func (lc LoxClass) call(arguments []interface{}) interface{} {
-	inst := &LoxInstance{class: &lc, fields: make(map[string]interface{})}
+func (lc *LoxClass) call(arguments []interface{}) (interface{}, *RuntimeError) {
+	inst := &LoxInstance{class: lc, fields: make(map[string]interface{})}
 	initializer := lc.findMethod("init")
 	if initializer != nil {
-		initializer.bind(inst).call(arguments)
+		if _, err := initializer.bind(inst).call(arguments); err != nil {
+			return nil, err
+		}
 	}
 
-	return inst
+	return inst, nil
 }
 
-func (lc LoxClass) arity() int {
+func (lc *LoxClass) arity() (int, int) {
 	initializer := lc.findMethod("init")
 	if initializer == nil {
-		return 0
+		return 0, 0
 	}
 	return initializer.arity()
 }
 
+func (lc *LoxClass) parameters() []string {
+	initializer := lc.findMethod("init")
+	if initializer == nil {
+		return nil
+	}
+	return initializer.parameters()
+}
+
 func (lc *LoxClass) findMethod(name string) *LoxFunction {
 	if f, exists := lc.methods[name]; exists {
 		return f
@@ -37,3 +49,23 @@ func (lc *LoxClass) findMethod(name string) *LoxFunction {
 	}
 	return nil
 }
+
+func (lc *LoxClass) findGetter(name string) *LoxFunction {
+	if f, exists := lc.getters[name]; exists {
+		return f
+	}
+	if lc.superclass != nil {
+		return lc.superclass.findGetter(name)
+	}
+	return nil
+}
+
+func (lc *LoxClass) findSetter(name string) *LoxFunction {
+	if f, exists := lc.setters[name]; exists {
+		return f
+	}
+	if lc.superclass != nil {
+		return lc.superclass.findSetter(name)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package lox
+
+import "testing"
+
+func TestGettersAndSetters(t *testing.T) {
+	checkScripts(t, []scriptTest{
+		{name: "getter", source: `
+class Circle {
+  init(r) { this.r = r; }
+  area { return 3 * this.r * this.r; }
+}
+print Circle(2).area;`, want: "12\n"},
+		{name: "setter", source: `
+class Temp {
+  set celsius(c) { this.f = c * 9 / 5 + 32; }
+  celsius { return (this.f - 32) * 5 / 9; }
+}
+var t = Temp();
+t.celsius = 100;
+print t.f;
+print t.celsius;`, want: "212\n100\n"},
+		{name: "inherited", source: `
+class A { name { return "a"; } }
+class B < A {}
+print B().name;`, want: "a\n"},
+		{name: "read only", source: `
+class A { x { return 1; } }
+A().x = 2;`, err: "Can't assign to read-only property 'x'."},
+		{name: "init getter", source: `class A { init { return 1; } }`,
+			err: "Can't use 'init' as a getter."},
+		{name: "init setter", source: `class A { set init(v) {} }`,
+			err: "Can't use 'init' as a setter."},
+	})
+}
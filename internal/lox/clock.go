@@ -2,21 +2,49 @@ package lox
 
 import "time"
 
+// Clock is where the interpreter gets the time from: clock(), the time module
+// and timers all use it. Tests can substitute a fake clock with SetClock to
+// make scripts deterministic.
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+}
+
+// systemClock is the real clock.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+// SetClock makes the interpreter use clock for all time sources.
+func (i *Interpreter) SetClock(clock Clock) {
+	i.clock = clock
+	i.started = clock.Now()
+}
+
 // ClockFunction implements the Callable interface, acting as a native function.
-type ClockFunction struct{}
+// It reads the time from its interpreter's clock.
+type ClockFunction struct {
+	interpreter *Interpreter
+}
 
 // call method returns the current time in seconds since the epoch.
-func (c ClockFunction) call(arguments []interface{}) interface{} {
+func (c ClockFunction) call(arguments []interface{}) (interface{}, *RuntimeError) {
 	// Return the current time in seconds as a floating-point number
-	return float64(time.Now().UnixMilli()) / 1000.0
+	return float64(c.interpreter.clock.Now().UnixMilli()) / 1000.0, nil
 }
 
 // arity method returns 0 because this function expects no arguments.
-func (c ClockFunction) arity() int {
-	return 0
+func (c ClockFunction) arity() (int, int) {
+	return 0, 0
 }
 
 // String method provides a string representation of the function.
 func (c ClockFunction) toString() string {
-	return "<native fn>"
+	return "<native fn clock>"
 }
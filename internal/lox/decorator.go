@@ -0,0 +1,31 @@
+package lox
+
+// Decorators are callables written as `@expr` above a function, method or
+// class. Each receives the declared value and returns its replacement; the one
+// closest to the declaration is applied first.
+
+// evaluateDecorators evaluates decorator expressions, top to bottom.
+func evaluateDecorators(exprs []Expr) ([]interface{}, *RuntimeError) {
+	var decorators []interface{}
+	for _, expr := range exprs {
+		decorator, err := expr.Eval()
+		if err != nil {
+			return nil, err
+		}
+		decorators = append(decorators, decorator)
+	}
+	return decorators, nil
+}
+
+// decorate applies decorators to value, bottom to top. Errors are reported at
+// name, the name of the declaration.
+func decorate(decorators []interface{}, value interface{}, name Token) (interface{}, *RuntimeError) {
+	for i := len(decorators) - 1; i >= 0; i-- {
+		var err *RuntimeError
+		value, err = callValue(decorators[i], name, []*Token{nil}, []interface{}{value})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,41 @@
+package lox
+
+import "testing"
+
+func TestDecorators(t *testing.T) {
+	checkScripts(t, []scriptTest{
+		{name: "function", source: `
+fun logged(f) { return (...a) => { print "call"; return f(...a); }; }
+@logged
+fun add(a, b) { return a + b; }
+print add(1, 2);`, want: "call\n3\n"},
+		{name: "stacked", source: `
+fun twice(f) { return (x) => f(f(x)); }
+fun plusTen(f) { return (x) => f(x) + 10; }
+@twice @plusTen
+fun inc(x) { return x + 1; }
+print inc(0);`, want: "22\n"},
+		{name: "method keeps this", source: `
+fun logged(f) { return (...a) => { print "call"; return f(...a); }; }
+class C {
+  init() { this.calls = 0; }
+  @logged
+  square(x) { this.calls = this.calls + 1; return x * x; }
+}
+var c = C();
+print c.square(3);
+print c.calls;
+print C().calls;`, want: "call\n9\n1\n0\n"},
+		{name: "class", source: `
+fun singleton(cls) { var instance = cls(); return () => instance; }
+@singleton
+class S { init() { print "made"; } }
+print S() == S();`, want: "made\ntrue\n"},
+		{name: "initializer", source: `fun d(f) { return f; } class A { @d init() {} }`,
+			err: "Can't decorate an initializer."},
+		{name: "getter", source: `fun d(f) { return f; } class A { @d x { return 1; } }`,
+			err: "Can only decorate methods, not getters or setters."},
+		{name: "not a declaration", source: `fun d(f) { return f; } @d var a = 1;`,
+			err: "Expect function or class after decorator."},
+	})
+}
@@ -0,0 +1,57 @@
+package lox
+
+import "fmt"
+
+// LoxEnum is the runtime value of an enum declaration.
+type LoxEnum struct {
+	name    string
+	members []*LoxEnumValue
+	methods map[string]*LoxFunction
+}
+
+// LoxEnumValue is a single member of an enum. Members are only ever created
+// when the enum is declared, so they compare equal by identity.
+type LoxEnumValue struct {
+	enum    *LoxEnum
+	name    string
+	ordinal int
+}
+
+func (le *LoxEnum) toString() string {
+	return "<enum " + le.name + ">"
+}
+
+func (le *LoxEnum) get(name Token) (interface{}, *RuntimeError) {
+	for _, member := range le.members {
+		if member.name == name.Lexeme {
+			return member, nil
+		}
+	}
+	if name.Lexeme == "values" {
+		values := make([]interface{}, len(le.members))
+		for i, member := range le.members {
+			values[i] = member
+		}
+		return NewList(values), nil
+	}
+
+	return nil, &RuntimeError{Token: name, Message: fmt.Sprintf("Undefined enum member '%v'.", name.Lexeme)}
+}
+
+func (lv *LoxEnumValue) toString() string {
+	return lv.enum.name + "." + lv.name
+}
+
+func (lv *LoxEnumValue) get(name Token) (interface{}, *RuntimeError) {
+	switch name.Lexeme {
+	case "name":
+		return lv.name, nil
+	case "ordinal":
+		return float64(lv.ordinal), nil
+	}
+	if method, exists := lv.enum.methods[name.Lexeme]; exists {
+		return method.bind(lv), nil
+	}
+
+	return nil, &RuntimeError{Token: name, Message: fmt.Sprintf("Undefined property '%v'.", name.Lexeme)}
+}
@@ -0,0 +1,25 @@
+package lox
+
+import "testing"
+
+func TestEnums(t *testing.T) {
+	checkScripts(t, []scriptTest{
+		{name: "members", source: `
+enum Color { Red, Green, Blue }
+print Color.Green;
+print Color.Green.name;
+print Color.Green.ordinal;
+print Color.values;
+print Color.Red == Color.Red;
+print Color.Red == Color.Blue;`, want: "Color.Green\nGreen\n1\n[Color.Red, Color.Green, Color.Blue]\ntrue\nfalse\n"},
+		{name: "methods", source: `
+enum Color { Red, Green; describe() { return this.name + "!"; } }
+print Color.Green.describe();`, want: "Green!\n"},
+		{name: "undefined member", source: `enum C { R } print C.G;`,
+			err: "Undefined enum member 'G'."},
+		{name: "duplicate member", source: `enum V { A, A }`,
+			err: "Duplicate enum member 'A'."},
+		{name: "values member", source: `enum V { values, A }`,
+			err: "Can't use 'values' as an enum member name."},
+	})
+}
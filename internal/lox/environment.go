@@ -4,6 +4,7 @@ import "fmt"
 
 type Environment struct {
 	values    map[string]interface{}
+	constants map[string]bool
 	enclosing *Environment
 }
 
@@ -17,6 +18,20 @@ func (e *Environment) define(name string, value interface{}) {
 	e.values[name] = value
 }
 
+// defineConstant defines a name that can never be assigned to again.
+func (e *Environment) defineConstant(name string, value interface{}) {
+	if e.constants == nil {
+		e.constants = make(map[string]bool)
+	}
+	e.values[name] = value
+	e.constants[name] = true
+}
+
+// isConstant reports whether name was declared with const in this environment.
+func (e *Environment) isConstant(name string) bool {
+	return e.constants[name]
+}
+
 func (e *Environment) ancestor(distance int) *Environment {
 	env := e
 	for i := 0; i < distance; i++ {
@@ -40,19 +55,21 @@ func (e *Environment) get(name Token) (interface{}, *RuntimeError) {
 		return e.enclosing.get(name)
 	}
 
-	return nil, &RuntimeError{name, fmt.Sprintf("Undefined variable '%v'.", name.Lexeme)}
+	return nil, &RuntimeError{Token: name, Message: fmt.Sprintf("Undefined variable '%v'.", name.Lexeme)}
 }
 
 func (e *Environment) assign(name Token, value interface{}) *RuntimeError {
 	if _, ok := e.values[name.Lexeme]; ok {
+		if e.isConstant(name.Lexeme) {
+			return &RuntimeError{Token: name, Message: fmt.Sprintf("Can't assign to constant '%v'.", name.Lexeme)}
+		}
 		e.values[name.Lexeme] = value
 		return nil
 	}
 
 	if e.enclosing != nil {
-		e.enclosing.assign(name, value)
-		return nil
+		return e.enclosing.assign(name, value)
 	}
 
-	return &RuntimeError{name, fmt.Sprintf("Undefined variable %v", name.Lexeme)}
+	return &RuntimeError{Token: name, Message: fmt.Sprintf("Undefined variable %v", name.Lexeme)}
 }
@@ -0,0 +1,20 @@
+package lox
+
+import "testing"
+
+func TestConst(t *testing.T) {
+	checkScripts(t, []scriptTest{
+		{name: "read", source: `const x = 1; print x;`, want: "1\n"},
+		{name: "contents stay mutable", source: `const l = List(); l.push(1); print l;`, want: "[1]\n"},
+		{name: "assign global", source: `const x = 1; x = 2;`,
+			err: "Can't assign to constant 'x'."},
+		{name: "assign local", source: `fun f() { const y = 1; y = 2; }`,
+			err: "Can't assign to constant 'y'."},
+		{name: "assign captured", source: `fun f() { const y = 1; fun g() { y = 2; } }`,
+			err: "Can't assign to constant 'y'."},
+		{name: "redeclare", source: `const x = 1; var x = 2;`,
+			err: "Can't redeclare constant 'x'."},
+		{name: "no initializer", source: `const x;`,
+			err: "Expect '=' after constant name."},
+	})
+}
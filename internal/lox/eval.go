@@ -2,14 +2,17 @@ package lox
 
 import (
 	"fmt"
-	"os"
-	"strings"
 )
 
 // Assuming RuntimeError is a custom error type
 type RuntimeError struct {
 	Token   Token
 	Message string
+	// trace is the Lox call stack where the error was raised, once it has
+	// unwound through a call.
+	trace []string
+	// shortCircuit marks the error that unwinds an optional chain.
+	shortCircuit bool
 }
 
 // Implement the Error() method to satisfy the error interface
@@ -21,19 +24,41 @@ func (l *Literal) Eval() (interface{}, *RuntimeError) {
 	return l.value, nil
 }
 func (l *Logical) Eval() (interface{}, *RuntimeError) {
-	left, _ := l.left.Eval()
+	left, err := l.left.Eval()
+	if err != nil {
+		return nil, err
+	}
 
-	if l.operator.Type == OR {
+	switch l.operator.Type {
+	case OR:
 		if isTruthy(left) {
 			return left, nil
-		} else {
-			if !isTruthy(left) {
-				return left, nil
-			}
+		}
+	case AND:
+		if !isTruthy(left) {
+			return left, nil
+		}
+	case QUESTION_QUESTION:
+		if left != nil {
+			return left, nil
 		}
 	}
 	return l.right.Eval()
 }
+
+// shortCircuitError unwinds an optional chain from the first nil link, at
+// name, up to the enclosing Optional, which turns it into nil.
+func shortCircuitError(name Token) *RuntimeError {
+	return &RuntimeError{Token: name, Message: "short-circuit", shortCircuit: true}
+}
+
+func (o *Optional) Eval() (interface{}, *RuntimeError) {
+	value, err := o.expression.Eval()
+	if err != nil && err.shortCircuit {
+		return nil, nil
+	}
+	return value, err
+}
 func (s *Set) Eval() (interface{}, *RuntimeError) {
 	object, err := s.object.Eval()
 	if err != nil {
@@ -41,20 +66,26 @@ func (s *Set) Eval() (interface{}, *RuntimeError) {
 	}
 
 	if _, ok := object.(*LoxInstance); !ok {
-		return nil, &RuntimeError{s.name, "Only instances have fields."}
+		return nil, &RuntimeError{Token: s.name, Message: "Only instances have fields."}
 	}
 
 	value, err := s.value.Eval()
 	if err != nil {
 		return nil, err
 	}
-	object.(*LoxInstance).set(s.name, value)
+	if err := object.(*LoxInstance).set(s.name, value); err != nil {
+		return nil, err
+	}
 	return value, nil
 }
 func (s *Super) Eval() (interface{}, *RuntimeError) {
 	distance := interpreter.locals[s]
 	sc, _ := interpreter.environment.getAt(distance, "super")
-	superclass := sc.(*LoxClass)
+	superclass, ok := sc.(*LoxClass)
+	if !ok {
+		// Only reachable from a trait method mixed into a class with no superclass.
+		return nil, &RuntimeError{Token: s.keyword, Message: "Can't use 'super' in a class with no superclass."}
+	}
 
 	obj, _ := interpreter.environment.getAt(distance-1, "this")
 	object := obj.(*LoxInstance)
@@ -62,9 +93,9 @@ func (s *Super) Eval() (interface{}, *RuntimeError) {
 	method := superclass.findMethod(s.method.Lexeme)
 
 	if method == nil {
-		return nil, &RuntimeError{s.method, fmt.Sprintf("Undefined property '%s'.", s.method.Lexeme)}
+		return nil, &RuntimeError{Token: s.method, Message: fmt.Sprintf("Undefined property '%s'.", s.method.Lexeme)}
 	}
-	return method.bind(object), nil
+	return object.bindMethod(method)
 }
 func (t *This) Eval() (interface{}, *RuntimeError) {
 	return lookupVariable(t.keyword, t)
@@ -91,92 +122,299 @@ func (b *Binary) Eval() (interface{}, *RuntimeError) {
 		return !isEqual(left, right), nil
 	case EQUAL_EQUAL:
 		return isEqual(left, right), nil
+	case IS:
+		return isInstance(left, right, b.operator)
 	case GREATER:
 		if !leftOk || !rightOK {
-			return nil, &RuntimeError{b.operator, "operands must be numbers"}
+			return nil, &RuntimeError{Token: b.operator, Message: "operands must be numbers"}
 		}
 		return leftNumber > rightNumber, nil
 	case GREATER_EQUAL:
 		if !leftOk || !rightOK {
-			return nil, &RuntimeError{b.operator, "operands must be numbers"}
+			return nil, &RuntimeError{Token: b.operator, Message: "operands must be numbers"}
 		}
 		return leftNumber >= rightNumber, nil
 	case LESS:
 		if !leftOk || !rightOK {
-			return nil, &RuntimeError{b.operator, "operands must be numbers"}
+			return nil, &RuntimeError{Token: b.operator, Message: "operands must be numbers"}
 		}
 		return leftNumber < rightNumber, nil
 	case LESS_EQUAL:
 		if !leftOk || !rightOK {
-			return nil, &RuntimeError{b.operator, "operands must be numbers"}
+			return nil, &RuntimeError{Token: b.operator, Message: "operands must be numbers"}
 		}
 		return leftNumber <= rightNumber, nil
 	case MINUS:
 		if !leftOk || !rightOK {
-			return nil, &RuntimeError{b.operator, "operands must be numbers"}
+			return nil, &RuntimeError{Token: b.operator, Message: "operands must be numbers"}
 		}
 		return leftNumber - rightNumber, nil
 	case SLASH:
 		if !leftOk || !rightOK {
-			return nil, &RuntimeError{b.operator, "operands must be numbers"}
+			return nil, &RuntimeError{Token: b.operator, Message: "operands must be numbers"}
 		}
 		return leftNumber / rightNumber, nil
 	case STAR:
 		if !leftOk || !rightOK {
-			return nil, &RuntimeError{b.operator, "operands must be numbers"}
+			return nil, &RuntimeError{Token: b.operator, Message: "operands must be numbers"}
 		}
 		return leftNumber * rightNumber, nil
 	case PLUS:
 		if leftOk && rightOK {
 			return leftNumber + rightNumber, nil
 		}
-		leftString, leftOk := left.(string)
-		rightString, rightOK := right.(string)
+		_, leftOk := left.(string)
+		_, rightOK := right.(string)
 
-		if leftOk && rightOK {
+		// A string concatenates with another string, or with an instance whose
+		// toString() converts it.
+		if leftOk && (rightOK || hasToString(right)) || rightOK && hasToString(left) {
+			leftString, err := stringify(left)
+			if err != nil {
+				return nil, err
+			}
+			rightString, err := stringify(right)
+			if err != nil {
+				return nil, err
+			}
 			return leftString + rightString, nil
 		}
-		return nil, &RuntimeError{b.operator, "operands must be two numbers or two strings"}
+		return nil, &RuntimeError{Token: b.operator, Message: "operands must be two numbers or two strings"}
 	}
 
 	return nil, nil
 }
 func (c *Call) Eval() (interface{}, *RuntimeError) {
-	callee, _ := c.callee.Eval()
+	callee, err := c.callee.Eval()
+	if err != nil {
+		return nil, err
+	}
 
+	names, arguments, err := c.evaluateArguments()
+	if err != nil {
+		return nil, err
+	}
+	return callValue(callee, c.paren, names, arguments)
+}
+
+// evaluateArguments evaluates the arguments of the call in order, expanding
+// spread lists. names holds the name of each named argument and nil otherwise.
+func (c *Call) evaluateArguments() ([]*Token, []interface{}, *RuntimeError) {
 	var arguments []interface{}
-	for _, arg := range c.arguments {
-		val, _ := arg.Eval()
+	var names []*Token
+	for i, arg := range c.arguments {
+		if spread, ok := arg.(*Spread); ok {
+			elements, err := spread.elements()
+			if err != nil {
+				return nil, nil, err
+			}
+			arguments = append(arguments, elements...)
+			names = append(names, make([]*Token, len(elements))...)
+			continue
+		}
+		val, err := arg.Eval()
+		if err != nil {
+			return nil, nil, err
+		}
 		arguments = append(arguments, val)
+		names = append(names, c.names[i])
 	}
+	return names, arguments, nil
+}
 
+// callValue calls callee with already evaluated arguments. paren locates the
+// call for error reporting.
+func callValue(callee interface{}, paren Token, names []*Token, arguments []interface{}) (interface{}, *RuntimeError) {
 	function, ok := callee.(Callable)
 	if !ok {
 		return nil, &RuntimeError{
-			// Assuming c.paren is the token that represents the call
-			Token:   c.paren,
+			Token:   paren,
 			Message: "Can only call functions and classes.",
 		}
 	}
 
-	if len(arguments) != function.arity() {
-		return nil, &RuntimeError{
-			// Assuming c.paren is the token that represents the call
-			Token:   c.paren,
-			Message: fmt.Sprintf("Expected %v arguments but got %v.", function.arity(), len(arguments)),
+	arguments, err := bindArguments(function, paren, names, arguments)
+	if err != nil {
+		return nil, err
+	}
+	interpreter.frames = append(interpreter.frames, frame{function.toString(), paren.Line})
+	defer func() {
+		interpreter.frames = interpreter.frames[:len(interpreter.frames)-1]
+	}()
+
+	value, err := function.call(arguments)
+	if err != nil && (err.Token.Line == 0 || err.trace == nil) {
+		// Annotate a copy, as the callee may still hold on to its error.
+		annotated := *err
+		if annotated.Token.Line == 0 {
+			// Native functions don't know where they were called from.
+			annotated.Token = paren
+		}
+		if annotated.trace == nil {
+			annotated.trace = interpreter.stackTrace(&annotated)
 		}
+		err = &annotated
+	}
+	return value, err
+}
+
+// Eval passes the left operand as the first argument to the right one. When
+// the right operand is a call, the remaining arguments follow it.
+func (p *Pipe) Eval() (interface{}, *RuntimeError) {
+	value, err := p.left.Eval()
+	if err != nil {
+		return nil, err
+	}
+
+	// An optional chain such as obj?.m(a) is wrapped in an Optional, which
+	// turns its short-circuit into nil once the call is unwrapped here.
+	right, optional := p.right, false
+	if o, ok := right.(*Optional); ok {
+		right, optional = o.expression, true
+	}
+	if call, ok := right.(*Call); ok {
+		callee, err := call.callee.Eval()
+		if err != nil && err.shortCircuit && optional {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		names, arguments, err := call.evaluateArguments()
+		if err != nil {
+			return nil, err
+		}
+		names = append([]*Token{nil}, names...)
+		arguments = append([]interface{}{value}, arguments...)
+		return callValue(callee, call.paren, names, arguments)
+	}
+
+	callee, err := p.right.Eval()
+	if err != nil {
+		return nil, err
 	}
-	return function.call(arguments), nil
+	return callValue(callee, p.operator, []*Token{nil}, []interface{}{value})
+}
+func (s *Spread) Eval() (interface{}, *RuntimeError) {
+	return nil, &RuntimeError{Token: s.operator, Message: "Can only spread arguments in a call."}
+}
+
+// elements evaluates the spread expression to the arguments it expands to.
+func (s *Spread) elements() ([]interface{}, *RuntimeError) {
+	value, err := s.expression.Eval()
+	if err != nil {
+		return nil, err
+	}
+	list, ok := value.(*LoxList)
+	if !ok {
+		return nil, &RuntimeError{Token: s.operator, Message: "Can only spread lists."}
+	}
+	return list.elements, nil
 }
 func (g *Get) Eval() (interface{}, *RuntimeError) {
 	object, err := g.object.Eval()
 	if err != nil {
 		return nil, err
 	}
-	if inst, ok := object.(*LoxInstance); ok {
-		return inst.get(g.name)
+	if object == nil && g.optional {
+		return nil, shortCircuitError(g.name)
+	}
+	if holder, ok := object.(PropertyHolder); ok {
+		return holder.get(g.name)
+	}
+	if s, ok := object.(string); ok {
+		return stringProperty(s, g.name)
 	}
-	return nil, &RuntimeError{g.name, "Only instances have properties."}
+	return nil, &RuntimeError{Token: g.name, Message: "Only instances have properties."}
+}
+
+// Eval indexes or slices a string, by rune, or a list, or looks up a key in
+// a map.
+func (i *Index) Eval() (interface{}, *RuntimeError) {
+	object, err := i.object.Eval()
+	if err != nil {
+		return nil, err
+	}
+	var start, end interface{}
+	if i.start != nil {
+		if start, err = i.start.Eval(); err != nil {
+			return nil, err
+		}
+	}
+	if i.end != nil {
+		if end, err = i.end.Eval(); err != nil {
+			return nil, err
+		}
+	}
+
+	switch v := object.(type) {
+	case string:
+		runes := []rune(v)
+		if !i.slice {
+			n, err := position(i.bracket, "String", start, len(runes)-1)
+			if err != nil {
+				return nil, err
+			}
+			return string(runes[n]), nil
+		}
+		from, to, err := sliceBounds(i.bracket, "String", start, end, len(runes))
+		if err != nil {
+			return nil, err
+		}
+		return string(runes[from:to]), nil
+	case *LoxList:
+		if !i.slice {
+			n, err := position(i.bracket, "List", start, len(v.elements)-1)
+			if err != nil {
+				return nil, err
+			}
+			return v.elements[n], nil
+		}
+		from, to, err := sliceBounds(i.bracket, "List", start, end, len(v.elements))
+		if err != nil {
+			return nil, err
+		}
+		return NewList(append([]interface{}{}, v.elements[from:to]...)), nil
+	case *LoxMap:
+		if i.slice {
+			return nil, &RuntimeError{Token: i.bracket, Message: "Maps can't be sliced."}
+		}
+		return v.values[start], nil
+	}
+	return nil, &RuntimeError{Token: i.bracket, Message: "Only strings, lists and maps can be indexed."}
+}
+
+// position converts an index into a string or list to an int from 0 to last.
+func position(bracket Token, kind string, value interface{}, last int) (int, *RuntimeError) {
+	number, ok := value.(float64)
+	if !ok || number != float64(int(number)) {
+		return 0, &RuntimeError{Token: bracket, Message: kind + " index must be an integer."}
+	}
+	if number < 0 || int(number) > last {
+		return 0, &RuntimeError{Token: bracket, Message: kind + " index out of range."}
+	}
+	return int(number), nil
+}
+
+// sliceBounds converts the bounds of a slice, which default to the whole
+// string or list.
+func sliceBounds(bracket Token, kind string, start, end interface{}, length int) (int, int, *RuntimeError) {
+	from, to := 0, length
+	var err *RuntimeError
+	if start != nil {
+		if from, err = position(bracket, kind, start, length); err != nil {
+			return 0, 0, err
+		}
+	}
+	if end != nil {
+		if to, err = position(bracket, kind, end, length); err != nil {
+			return 0, 0, err
+		}
+	}
+	if from > to {
+		return 0, 0, &RuntimeError{Token: bracket, Message: "Slice start is after its end."}
+	}
+	return from, to, nil
 }
 func (u *Unary) Eval() (interface{}, *RuntimeError) {
 	right, err := u.right.Eval()
@@ -188,7 +426,7 @@ func (u *Unary) Eval() (interface{}, *RuntimeError) {
 		if number, ok := right.(float64); ok {
 			return -number, nil
 		} else {
-			return nil, &RuntimeError{u.operator, "operand must be a number"}
+			return nil, &RuntimeError{Token: u.operator, Message: "operand must be a number"}
 		}
 	case BANG:
 		return !isTruthy(right), nil
@@ -215,19 +453,24 @@ func (a *Assign) Eval() (interface{}, *RuntimeError) {
 	distance, exists := interpreter.locals[a]
 	if exists {
 		interpreter.environment.assignAt(distance, a.name, value)
-	} else {
-		interpreter.globals.assign(a.name, value)
+	} else if err := interpreter.globals.assign(a.name, value); err != nil {
+		return nil, err
 	}
 	return value, nil
 }
 func (af *AnonFunction) Eval() (interface{}, *RuntimeError) {
-	return &LoxFunction{
-		declaration: &Function{
-			name:   nil, // Anonymous functions have no name
-			params: af.params,
-			body:   af.body,
-		},
-	}, nil
+	return &LoxFunction{declaration: af.function, closure: interpreter.environment}, nil
+}
+
+// evaluateIn evaluates expr with env as the current environment.
+func evaluateIn(env *Environment, expr Expr) (interface{}, *RuntimeError) {
+	previous := interpreter.environment
+	defer func() {
+		interpreter.environment = previous
+	}()
+
+	interpreter.environment = env
+	return expr.Eval()
 }
 func isTruthy(e interface{}) bool {
 	if e == nil {
@@ -243,26 +486,16 @@ func isEqual(a interface{}, b interface{}) bool {
 	return a == b
 }
 
-type Stringer interface {
-	toString() string
-}
-
-func stringify(value interface{}) string {
-	switch v := value.(type) {
-	case float64:
-		// Convert the float32 to a string
-		text := fmt.Sprintf("%f", v)
-		// If it ends with ".0", remove it
-		text = strings.TrimSuffix(text, ".000000")
-		return text
-	case Stringer:
-		return v.toString()
-	default:
-		// Fallback for other types, using fmt.Sprintf to handle them
-		return fmt.Sprintf("%v", value)
-	}
+// PropertyHolder is implemented by values whose properties can be read with '.'.
+type PropertyHolder interface {
+	get(name Token) (interface{}, *RuntimeError)
 }
 
 func handleRuntimeError(err *RuntimeError) {
-	fmt.Fprintf(os.Stderr, "[line %d]{%v} %s\n", err.Token.Line, err.Token.Lexeme, err.Error())
+	if interpreter.exited {
+		// Not an error: exit() unwinding.
+		return
+	}
+	interpreter.hadRuntimeError = true
+	fmt.Fprintf(interpreter.stderr, "[line %d]{%v} %s\n", err.Token.Line, err.Token.Lexeme, err.Error())
 }
@@ -0,0 +1,54 @@
+package lox
+
+import "testing"
+
+func TestOptionalChaining(t *testing.T) {
+	checkScripts(t, []scriptTest{
+		{name: "nil", source: `
+var a;
+print a?.b == nil;
+print a?.b.c == nil;
+print a?.m(1).z == nil;`, want: "true\ntrue\ntrue\n"},
+		{name: "instance", source: `
+class A { init() { this.x = 1; } m() { return 2; } }
+var a = A();
+print a?.x;
+print a?.m();`, want: "1\n2\n"},
+		{name: "skips arguments", source: `
+fun loud() { print "evaluated"; return 1; }
+var a;
+print a?.m(loud()) == nil;`, want: "true\n"},
+		{name: "non-instance", source: `var x = 1; print x?.y;`,
+			err: "Only instances have properties."},
+	})
+}
+
+func TestNilCoalescing(t *testing.T) {
+	checkScripts(t, []scriptTest{
+		{name: "nil", source: `var a; print a ?? 3;`, want: "3\n"},
+		{name: "falsey values kept", source: `print 0 ?? 3; print false ?? 1;`, want: "0\nfalse\n"},
+		{name: "short-circuits", source: `
+fun loud() { print "evaluated"; return 1; }
+print 2 ?? loud();`, want: "2\n"},
+		{name: "chained", source: `var a; var b; print a ?? b ?? "c";`, want: "c\n"},
+	})
+}
+
+func TestPipe(t *testing.T) {
+	checkScripts(t, []scriptTest{
+		{name: "chain", source: `
+fun double(x) { return x * 2; }
+fun add(a, b) { return a + b; }
+print 3 |> double |> add(1);`, want: "7\n"},
+		{name: "arrow", source: `print 5 |> (x) => x + 1;`, want: "6\n"},
+		{name: "named arguments", source: `fun sub(a, b) { return a - b; } print 2 |> sub(b: 5);`, want: "-3\n"},
+		{name: "optional call", source: `
+class M { m(x, y) { return x - y; } }
+var o = M();
+var n;
+print 10 |> o?.m(4);
+print 10 |> n?.m(4);`, want: "6\nnil\n"},
+		{name: "not callable", source: `print 1 |> 2;`,
+			err: "Can only call functions and classes."},
+	})
+}
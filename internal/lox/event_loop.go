@@ -0,0 +1,191 @@
+package lox
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EventLoop runs callbacks queued by promises and timers on the interpreter's
+// single thread.
+type EventLoop struct {
+	interpreter *Interpreter
+	tasks       []func()
+	timers      []*timer
+	nextID      int
+	// rejections holds every rejected promise, to report the unhandled ones.
+	rejections []*LoxPromise
+	// suspended holds the coroutines waiting at an await.
+	suspended map[*coroutine]bool
+}
+
+type timer struct {
+	id       int
+	due      time.Time
+	interval time.Duration
+	repeat   bool
+	callback func()
+}
+
+func NewEventLoop(interpreter *Interpreter) *EventLoop {
+	return &EventLoop{interpreter: interpreter, suspended: make(map[*coroutine]bool)}
+}
+
+func (l *EventLoop) enqueue(task func()) {
+	l.tasks = append(l.tasks, task)
+}
+
+// schedule runs callback after delay, and then every delay if repeat is set.
+// It returns an id for cancel.
+func (l *EventLoop) schedule(delay time.Duration, repeat bool, callback func()) int {
+	if repeat && delay < time.Millisecond {
+		delay = time.Millisecond
+	}
+	l.nextID++
+	l.timers = append(l.timers, &timer{
+		id:       l.nextID,
+		due:      l.interpreter.clock.Now().Add(delay),
+		interval: delay,
+		repeat:   repeat,
+		callback: callback,
+	})
+	return l.nextID
+}
+
+func (l *EventLoop) cancel(id int) {
+	for i, t := range l.timers {
+		if t.id == id {
+			l.timers = append(l.timers[:i], l.timers[i+1:]...)
+			return
+		}
+	}
+}
+
+// run drains the loop and then reports rejections that were never handled.
+// Coroutines still suspended by then can never resume, so they are cancelled.
+func (l *EventLoop) run() {
+	l.runUntil(func() bool { return false })
+	for co := range l.suspended {
+		co.cancel()
+	}
+
+	for _, promise := range l.rejections {
+		if !promise.handled && !l.interpreter.exited {
+			l.reportRejection(promise.err)
+		}
+	}
+	l.rejections = nil
+}
+
+// runUntil runs queued tasks and due timers until done reports true. It returns
+// false if the loop runs out of work first.
+func (l *EventLoop) runUntil(done func() bool) bool {
+	for !done() && !l.interpreter.exited {
+		if !l.step() {
+			return false
+		}
+	}
+	return true
+}
+
+// step runs the next queued task, or the next timer if it is due. Otherwise it
+// waits until the timer is due and returns, so that the timer is picked again:
+// other tasks run in the meantime and may cancel it. It returns false if there
+// is nothing left to run.
+func (l *EventLoop) step() bool {
+	if len(l.tasks) > 0 {
+		task := l.tasks[0]
+		l.tasks = l.tasks[1:]
+		task()
+		return true
+	}
+
+	next := l.nextTimer()
+	if next == nil {
+		return false
+	}
+	if wait := next.due.Sub(l.interpreter.clock.Now()); wait > 0 {
+		l.interpreter.unlocked(func() { l.interpreter.clock.Sleep(wait) })
+		return true
+	}
+	if next.repeat {
+		next.due = next.due.Add(next.interval)
+	} else {
+		l.cancel(next.id)
+	}
+	next.callback()
+	return true
+}
+
+// pending reports whether step has anything to run.
+func (l *EventLoop) pending() bool {
+	return len(l.tasks) > 0 || len(l.timers) > 0
+}
+
+func (l *EventLoop) nextTimer() *timer {
+	var next *timer
+	for _, t := range l.timers {
+		if next == nil || t.due.Before(next.due) {
+			next = t
+		}
+	}
+	return next
+}
+
+func (l *EventLoop) reportRejection(err *RuntimeError) {
+	l.interpreter.hadRuntimeError = true
+	fmt.Fprintf(l.interpreter.stderr, "Unhandled promise rejection: %s\n", err.Error())
+	if len(err.trace) > 0 {
+		fmt.Fprintln(l.interpreter.stderr, strings.Join(err.trace, "\n"))
+	}
+}
+
+// defineEventLoopNatives registers the timer functions backed by i's event loop.
+func defineEventLoopNatives(i *Interpreter) {
+	timer := func(repeat bool) func([]interface{}) (interface{}, *RuntimeError) {
+		return func(arguments []interface{}) (interface{}, *RuntimeError) {
+			callback := arguments[0]
+			if _, ok := callback.(Callable); !ok {
+				return nil, &RuntimeError{Message: "Timer callback must be a function."}
+			}
+			delay, ok := arguments[1].(float64)
+			if !ok {
+				return nil, &RuntimeError{Message: "Timer delay must be a number of milliseconds."}
+			}
+			id := i.loop.schedule(milliseconds(delay), repeat, func() {
+				if _, err := callValue(callback, Token{}, nil, nil); err != nil {
+					handleRuntimeError(err)
+				}
+			})
+			return float64(id), nil
+		}
+	}
+	clear := func(arguments []interface{}) (interface{}, *RuntimeError) {
+		id, ok := arguments[0].(float64)
+		if !ok {
+			return nil, &RuntimeError{Message: "Timer id must be a number."}
+		}
+		i.loop.cancel(int(id))
+		return nil, nil
+	}
+
+	i.globals.define("setTimeout", &NativeFunction{"setTimeout", 2, 2, timer(false)})
+	i.globals.define("setInterval", &NativeFunction{"setInterval", 2, 2, timer(true)})
+	i.globals.define("clearTimeout", &NativeFunction{"clearTimeout", 1, 1, clear})
+	i.globals.define("clearInterval", &NativeFunction{"clearInterval", 1, 1, clear})
+	i.globals.define("sleep", &NativeFunction{"sleep", 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		delay, ok := arguments[0].(float64)
+		if !ok {
+			return nil, &RuntimeError{Message: "Sleep duration must be a number of milliseconds."}
+		}
+		promise := &LoxPromise{}
+		i.loop.schedule(milliseconds(delay), false, func() {
+			promise.settle(nil, nil)
+		})
+		return promise, nil
+	}})
+}
+
+func milliseconds(ms float64) time.Duration {
+	return time.Duration(ms * float64(time.Millisecond))
+}
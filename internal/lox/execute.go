@@ -11,14 +11,22 @@ func (p *Print) Execute() *RuntimeError {
 	if err != nil {
 		return err
 	}
-	fmt.Printf("%v\n", stringify(value))
+	text, err := stringify(value)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(interpreter.stdout, text)
 	return nil
 }
 
 func (r *Return) Execute() *RuntimeError {
 	var value interface{}
 	if r.value != nil {
-		value, _ = r.value.Eval()
+		var err *RuntimeError
+		value, err = r.value.Eval()
+		if err != nil {
+			return err
+		}
 	}
 
 	panic(NewReturn(value))
@@ -32,11 +40,30 @@ func (e *Expression) Execute() *RuntimeError {
 	return nil
 }
 func (f *Function) Execute() *RuntimeError {
-	fun := &LoxFunction{declaration: f, closure: interpreter.environment}
+	if err := checkRedeclaration(*f.name); err != nil {
+		return err
+	}
+	decorators, err := evaluateDecorators(f.decorators)
+	if err != nil {
+		return err
+	}
+	fun, err := decorate(decorators, &LoxFunction{declaration: f, closure: interpreter.environment}, *f.name)
+	if err != nil {
+		return err
+	}
 	interpreter.environment.define(f.name.Lexeme, fun)
 	return nil
 }
 
+// checkRedeclaration rejects declarations that would replace a constant.
+// The resolver catches this for locals, so it only matters for globals.
+func checkRedeclaration(name Token) *RuntimeError {
+	if interpreter.environment.isConstant(name.Lexeme) {
+		return &RuntimeError{Token: name, Message: fmt.Sprintf("Can't redeclare constant '%v'.", name.Lexeme)}
+	}
+	return nil
+}
+
 func (i *If) Execute() *RuntimeError {
 	val, err := i.condition.Eval()
 	if isTruthy(val) {
@@ -57,7 +84,14 @@ func (v *Var) Execute() *RuntimeError {
 		}
 	}
 
-	interpreter.environment.define(v.name.Lexeme, value)
+	if err := checkRedeclaration(v.name); err != nil {
+		return err
+	}
+	if v.constant {
+		interpreter.environment.defineConstant(v.name.Lexeme, value)
+	} else {
+		interpreter.environment.define(v.name.Lexeme, value)
+	}
 	return nil
 }
 func (w *While) Execute() *RuntimeError {
@@ -72,7 +106,7 @@ func (w *While) Execute() *RuntimeError {
 
 		err = w.body.Execute()
 		if err != nil {
-			if err.Message == "break" {
+			if isBreak(err, "") {
 				break
 			}
 			return err
@@ -80,6 +114,65 @@ func (w *While) Execute() *RuntimeError {
 	}
 	return nil
 }
+func (d *DoWhile) Execute() *RuntimeError {
+	for {
+		err := d.body.Execute()
+		if err != nil {
+			if isBreak(err, "") {
+				break
+			}
+			return err
+		}
+
+		val, err := d.condition.Eval()
+		if err != nil {
+			return err
+		}
+		if !isTruthy(val) {
+			break
+		}
+	}
+	return nil
+}
+
+// Execute runs the labeled loop, stopping it when a break names its label.
+// Unlabeled breaks are handled by the loop itself.
+func (l *Labeled) Execute() *RuntimeError {
+	err := l.body.Execute()
+	if err != nil && isBreak(err, l.label.Lexeme) {
+		return nil
+	}
+	return err
+}
+
+// SwitchCase is one `case a, b: ...` arm of a switch statement.
+type SwitchCase struct {
+	values []Expr
+	body   *Block
+}
+
+func (s *Switch) Execute() *RuntimeError {
+	value, err := s.value.Eval()
+	if err != nil {
+		return err
+	}
+
+	for _, c := range s.cases {
+		for _, candidate := range c.values {
+			match, err := candidate.Eval()
+			if err != nil {
+				return err
+			}
+			if isEqual(value, match) {
+				return c.body.Execute()
+			}
+		}
+	}
+	if s.defaultBranch != nil {
+		return s.defaultBranch.Execute()
+	}
+	return nil
+}
 func (b *Block) Execute() *RuntimeError {
 	return executeBlock(b.statements, NewEnvironmentWithEnclosing(interpreter.environment))
 }
@@ -106,42 +199,166 @@ func (c *Class) Execute() *RuntimeError {
 	var superclass *LoxClass
 	var err *RuntimeError
 	var ok bool
+	decorators, err := evaluateDecorators(c.decorators)
+	if err != nil {
+		return err
+	}
 	if c.superclass != nil {
 		sc, err = c.superclass.Eval()
 		if err != nil {
 			return err
 		}
 		if superclass, ok = sc.(*LoxClass); !ok {
-			return &RuntimeError{c.superclass.name, "Superclass must be a class."}
+			return &RuntimeError{Token: c.superclass.name, Message: "Superclass must be a class."}
+		}
+	}
+	var traits []*LoxTrait
+	for _, t := range c.traits {
+		value, err := t.Eval()
+		if err != nil {
+			return err
 		}
+		trait, ok := value.(*LoxTrait)
+		if !ok {
+			return &RuntimeError{Token: t.name, Message: "Can only mix in traits."}
+		}
+		traits = append(traits, trait)
+	}
+	if err := checkRedeclaration(c.name); err != nil {
+		return err
 	}
 	interpreter.environment.define(c.name.Lexeme, nil)
 
 	if c.superclass != nil {
-		interpreter.environment = NewEnvironmentWithEnclosing(interpreter.environment)
+		enclosing := interpreter.environment
+		interpreter.environment = NewEnvironmentWithEnclosing(enclosing)
 		interpreter.environment.define("super", superclass)
+		defer func() {
+			interpreter.environment = enclosing
+		}()
 	}
 
-	methods := make(map[string]*LoxFunction)
+	methods, err := mixTraits(c, traits, superclass)
+	if err != nil {
+		return err
+	}
 	for _, method := range c.methods {
+		methodDecorators, err := evaluateDecorators(method.decorators)
+		if err != nil {
+			return err
+		}
 		function := &LoxFunction{
 			declaration:   method,
 			closure:       interpreter.environment,
 			isInitializer: method.name.Lexeme == "init",
+			decorators:    methodDecorators,
 		}
 		methods[method.name.Lexeme] = function
 	}
-	class := &LoxClass{name: c.name.Lexeme, methods: methods, superclass: superclass}
+	getters := make(map[string]*LoxFunction)
+	for _, getter := range c.getters {
+		getters[getter.name.Lexeme] = &LoxFunction{declaration: getter, closure: interpreter.environment}
+	}
+	setters := make(map[string]*LoxFunction)
+	for _, setter := range c.setters {
+		setters[setter.name.Lexeme] = &LoxFunction{declaration: setter, closure: interpreter.environment}
+	}
+	class := &LoxClass{
+		name:       c.name.Lexeme,
+		methods:    methods,
+		getters:    getters,
+		setters:    setters,
+		superclass: superclass,
+	}
 
-	if c.superclass != nil {
-		interpreter.environment = interpreter.environment.enclosing
+	value, err := decorate(decorators, class, c.name)
+	if err != nil {
+		return err
 	}
+	return interpreter.environment.assign(c.name, value)
+}
 
-	interpreter.environment.assign(c.name, class)
+// mixTraits copies the methods of each trait into a new method table for the
+// class. A method provided by more than one trait must be overridden by the
+// class itself.
+func mixTraits(c *Class, traits []*LoxTrait, superclass *LoxClass) (map[string]*LoxFunction, *RuntimeError) {
+	overridden := make(map[string]bool)
+	for _, method := range c.methods {
+		overridden[method.name.Lexeme] = true
+	}
+
+	methods := make(map[string]*LoxFunction)
+	providers := make(map[string]*LoxTrait)
+	for i, trait := range traits {
+		// Trait methods resolve 'super' one scope above 'this', like class methods do.
+		env := NewEnvironmentWithEnclosing(trait.closure)
+		if superclass != nil {
+			env.define("super", superclass)
+		}
+
+		for _, method := range trait.methods {
+			name := method.name.Lexeme
+			if other, exists := providers[name]; exists && other != trait && !overridden[name] {
+				return nil, &RuntimeError{Token: c.traits[i].name,
+					Message: fmt.Sprintf("Method '%v' from trait '%v' conflicts with trait '%v'.", name, trait.name, other.name)}
+			}
+			providers[name] = trait
+			methods[name] = &LoxFunction{
+				declaration:   method,
+				closure:       env,
+				isInitializer: name == "init",
+			}
+		}
+	}
+	return methods, nil
+}
+
+func (e *Enum) Execute() *RuntimeError {
+	if err := checkRedeclaration(e.name); err != nil {
+		return err
+	}
+
+	enum := &LoxEnum{name: e.name.Lexeme, methods: make(map[string]*LoxFunction)}
+	for i, member := range e.members {
+		enum.members = append(enum.members, &LoxEnumValue{enum: enum, name: member.Lexeme, ordinal: i})
+	}
+	for _, method := range e.methods {
+		enum.methods[method.name.Lexeme] = &LoxFunction{declaration: method, closure: interpreter.environment}
+	}
+
+	interpreter.environment.define(e.name.Lexeme, enum)
+	return nil
+}
+func (t *Trait) Execute() *RuntimeError {
+	if err := checkRedeclaration(t.name); err != nil {
+		return err
+	}
+	interpreter.environment.define(t.name.Lexeme, &LoxTrait{
+		name:    t.name.Lexeme,
+		methods: t.methods,
+		closure: interpreter.environment,
+	})
 	return nil
 }
 func (b *Break) Execute() *RuntimeError {
+	token := b.keyword
+	if b.label != nil {
+		token = *b.label
+	}
 	return &RuntimeError{
+		Token:   token,
 		Message: "break",
 	}
 }
+
+// isBreak reports whether err is a break out of the loop with the given label.
+// Unlabeled loops pass an empty label.
+func isBreak(err *RuntimeError, label string) bool {
+	if err.Message != "break" {
+		return false
+	}
+	if err.Token.Type == BREAK {
+		return label == ""
+	}
+	return err.Token.Lexeme == label
+}
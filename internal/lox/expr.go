@@ -4,11 +4,20 @@ package lox
 type Expr interface {
 Eval() (interface{}, *RuntimeError)
 }
+type AnonFunction struct {
+  function *Function
+}
+
 type Assign struct {
   name Token
   value Expr
 }
 
+type Await struct {
+  keyword Token
+  value Expr
+}
+
 type Binary struct {
   left Expr
   operator Token
@@ -19,17 +28,27 @@ type Call struct {
   callee Expr
   paren Token
   arguments []Expr
+  names []*Token
 }
 
 type Get struct {
   object Expr
   name Token
+  optional bool
 }
 
 type Grouping struct {
   expression Expr
 }
 
+type Index struct {
+  object Expr
+  bracket Token
+  start Expr
+  end Expr
+  slice bool
+}
+
 type Literal struct {
   value interface{}
 }
@@ -40,12 +59,32 @@ type Logical struct {
   right Expr
 }
 
+type Optional struct {
+  expression Expr
+}
+
+type Pipe struct {
+  left Expr
+  operator Token
+  right Expr
+}
+
 type Set struct {
   object Expr
   name Token
   value Expr
 }
 
+type Spawn struct {
+  keyword Token
+  call *Call
+}
+
+type Spread struct {
+  operator Token
+  expression Expr
+}
+
 type Super struct {
   keyword Token
   method Token
@@ -0,0 +1,238 @@
+package lox
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// formatSpec is the part of a placeholder after the colon:
+// [[fill]align][0][width][.precision][type]. align is '<', '>' or '^', and
+// type is one of f, e, d, x or s.
+type formatSpec struct {
+	fill      rune
+	align     rune
+	zero      bool
+	width     int
+	precision int
+	kind      byte
+}
+
+// formatString fills the placeholders in format with arguments. A placeholder
+// is written {} for the next argument or {n} for argument n, optionally
+// followed by a spec, as in {:>8.2f}. Braces are escaped by doubling them.
+func formatString(format string, arguments []interface{}) (string, *RuntimeError) {
+	var out strings.Builder
+	next := 0
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		if c == '}' {
+			if i+1 < len(format) && format[i+1] == '}' {
+				out.WriteByte('}')
+				i++
+				continue
+			}
+			return "", &RuntimeError{Message: "format(): a single '}' must be written as '}}'."}
+		}
+		if c != '{' {
+			out.WriteByte(c)
+			continue
+		}
+		if i+1 < len(format) && format[i+1] == '{' {
+			out.WriteByte('{')
+			i++
+			continue
+		}
+
+		end := strings.IndexByte(format[i:], '}')
+		if end < 0 {
+			return "", &RuntimeError{Message: "format(): unclosed '{'."}
+		}
+		placeholder := format[i+1 : i+end]
+		i += end
+
+		index, specText, _ := strings.Cut(placeholder, ":")
+		n := next
+		if index != "" {
+			parsed, err := strconv.Atoi(index)
+			if err != nil || parsed < 0 {
+				return "", &RuntimeError{Message: fmt.Sprintf("format(): invalid placeholder '{%v}'.", placeholder)}
+			}
+			n = parsed
+		} else {
+			next++
+		}
+		if n >= len(arguments) {
+			return "", &RuntimeError{Message: fmt.Sprintf("format(): no argument for placeholder %d.", n)}
+		}
+
+		spec, ok := parseFormatSpec(specText)
+		if !ok {
+			return "", &RuntimeError{Message: fmt.Sprintf("format(): invalid format spec '%v'.", specText)}
+		}
+		text, err := spec.apply(arguments[n])
+		if err != nil {
+			return "", err
+		}
+		out.WriteString(text)
+	}
+	return out.String(), nil
+}
+
+// maxFormatWidth bounds the width and precision of a format spec, so a typo
+// can't ask for gigabytes of padding.
+const maxFormatWidth = 1000
+
+func parseFormatSpec(text string) (formatSpec, bool) {
+	spec := formatSpec{fill: ' ', precision: -1}
+	isAlign := func(r rune) bool { return r == '<' || r == '>' || r == '^' }
+
+	runes := []rune(text)
+	if len(runes) >= 2 && isAlign(runes[1]) {
+		spec.fill, spec.align = runes[0], runes[1]
+		runes = runes[2:]
+	} else if len(runes) >= 1 && isAlign(runes[0]) {
+		spec.align = runes[0]
+		runes = runes[1:]
+	}
+	rest := string(runes)
+
+	if strings.HasPrefix(rest, "0") {
+		spec.zero = true
+		rest = rest[1:]
+	}
+	digits := len(rest) - len(strings.TrimLeft(rest, "0123456789"))
+	if digits > 0 {
+		width, err := strconv.Atoi(rest[:digits])
+		if err != nil || width > maxFormatWidth {
+			return spec, false
+		}
+		spec.width = width
+		rest = rest[digits:]
+	}
+	if strings.HasPrefix(rest, ".") {
+		rest = rest[1:]
+		digits = len(rest) - len(strings.TrimLeft(rest, "0123456789"))
+		if digits == 0 {
+			return spec, false
+		}
+		precision, err := strconv.Atoi(rest[:digits])
+		if err != nil || precision > maxFormatWidth {
+			return spec, false
+		}
+		spec.precision = precision
+		rest = rest[digits:]
+	}
+	if len(rest) > 1 || (rest != "" && !strings.Contains("fedxs", rest)) {
+		return spec, false
+	}
+	if rest != "" {
+		spec.kind = rest[0]
+	}
+	return spec, true
+}
+
+// apply formats one argument according to the spec.
+func (spec formatSpec) apply(value interface{}) (string, *RuntimeError) {
+	number, isNumber := value.(float64)
+	kind := spec.kind
+	if kind == 0 && isNumber && spec.precision >= 0 {
+		kind = 'f'
+	}
+
+	var text string
+	switch kind {
+	case 'f', 'e':
+		if !isNumber {
+			return "", &RuntimeError{Message: fmt.Sprintf("format(): {:%c} expects a number.", kind)}
+		}
+		precision := spec.precision
+		if precision < 0 {
+			precision = 6
+		}
+		text = strconv.FormatFloat(number, kind, precision, 64)
+		if math.IsInf(number, 0) || math.IsNaN(number) {
+			text = formatNumber(number)
+		}
+	case 'd', 'x':
+		if !isNumber || number != math.Trunc(number) || math.IsInf(number, 0) {
+			return "", &RuntimeError{Message: fmt.Sprintf("format(): {:%c} expects an integer.", kind)}
+		}
+		// Integers are formatted as an int64, which holds -2^63 up to 2^63-1.
+		if number < math.MinInt64 || number >= 1<<63 {
+			return "", &RuntimeError{Message: fmt.Sprintf("format(): %v is too large for {:%c}.", formatNumber(number), kind)}
+		}
+		base := 10
+		if kind == 'x' {
+			base = 16
+		}
+		text = strconv.FormatInt(int64(number), base)
+	default:
+		var err *RuntimeError
+		if text, err = stringify(value); err != nil {
+			return "", err
+		}
+		if spec.precision >= 0 && !isNumber && utf8.RuneCountInString(text) > spec.precision {
+			text = string([]rune(text)[:spec.precision])
+		}
+	}
+
+	// Numbers go to the right by default, everything else to the left.
+	align := spec.align
+	if align == 0 {
+		align = '<'
+		if isNumber {
+			align = '>'
+		}
+	}
+
+	padding := spec.width - utf8.RuneCountInString(text)
+	if padding <= 0 {
+		return text, nil
+	}
+	if spec.zero && isNumber && spec.align == 0 {
+		// Zeros go between the sign and the digits.
+		sign := ""
+		if strings.HasPrefix(text, "-") {
+			sign, text = "-", text[1:]
+		}
+		return sign + strings.Repeat("0", padding) + text, nil
+	}
+
+	fill := string(spec.fill)
+	switch align {
+	case '>':
+		return strings.Repeat(fill, padding) + text, nil
+	case '^':
+		left := padding / 2
+		return strings.Repeat(fill, left) + text + strings.Repeat(fill, padding-left), nil
+	}
+	return text + strings.Repeat(fill, padding), nil
+}
+
+// defineFormatNatives registers format and printf.
+func defineFormatNatives(i *Interpreter) {
+	i.globals.define("format", &NativeFunction{"format", 1, variadic, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		format, err := stringArgument("format", arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		return formatString(format, arguments[1:])
+	}})
+
+	// printf prints formatted text, without adding a newline.
+	i.globals.define("printf", &NativeFunction{"printf", 1, variadic, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		format, err := stringArgument("printf", arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		text, err := formatString(format, arguments[1:])
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprint(i.stdout, text)
+		return nil, nil
+	}})
+}
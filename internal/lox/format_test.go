@@ -0,0 +1,36 @@
+package lox
+
+import "testing"
+
+func TestFormat(t *testing.T) {
+	checkScripts(t, []scriptTest{
+		{name: "numbers", source: `print 0.1 + 0.2; print 3; print 2.5; print 1 / 3; print 1000000 * 1000000 * 1000000000; print 0 / 0;`,
+			want: "0.30000000000000004\n3\n2.5\n0.3333333333333333\n1e+21\nnan\n"},
+		{name: "format", source: `print format("[{:>6.2f}] [{:<4}] [{:^5}] [{:03d}] {{}}", 3.14159, "ab", "c", 7);`,
+			want: "[  3.14] [ab  ] [  c  ] [007] {}\n"},
+		{name: "printf", source: `printf("{} and {1}", "a", "b"); print "!";`, want: "a and b!\n"},
+		{name: "integers", source: `
+print format("{:x}", 255);
+print format("{:d}", -9223372036854775808);
+print format("{:05d}", -42);`, want: "ff\n-9223372036854775808\n-0042\n"},
+		{name: "fill and precision", source: `
+print format("[{:*^7}]", "ab");
+print format("{:.3}", "abcdef");
+print format("{:.1e}", 12345);
+print format("{:1000}", "a").length;`, want: "[**ab***]\nabc\n1.2e+04\n1000\n"},
+		{name: "huge width", source: `format("{:99999999999999999999}", 1);`,
+			err: "format(): invalid format spec '99999999999999999999'."},
+		{name: "width over limit", source: `format("{:1000000000}", 1);`,
+			err: "format(): invalid format spec '1000000000'."},
+		{name: "huge precision", source: `format("{:.99999999999999999999f}", 1);`,
+			err: "format(): invalid format spec '.99999999999999999999f'."},
+		{name: "integer overflow", source: `format("{:d}", 100000000000000000000000000000);`,
+			err: "format(): 1e+29 is too large for {:d}."},
+		{name: "not an integer", source: `format("{:d}", 1.5);`,
+			err: "format(): {:d} expects an integer."},
+		{name: "missing argument", source: `format("{} {}", 1);`,
+			err: "format(): no argument for placeholder 1."},
+		{name: "unmatched brace", source: `format("a }", 1);`,
+			err: "format(): a single '}' must be written as '}}'."},
+	})
+}
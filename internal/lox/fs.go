@@ -0,0 +1,270 @@
+package lox
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// AllowFS lets scripts use the fs module on files under the given roots.
+// Without any roots, every fs call fails.
+func (i *Interpreter) AllowFS(roots ...string) error {
+	for _, root := range roots {
+		abs, err := filepath.Abs(root)
+		if err != nil {
+			return err
+		}
+		resolved, err := filepath.EvalSymlinks(abs)
+		if err != nil {
+			return err
+		}
+		i.fsRoots = append(i.fsRoots, resolved)
+	}
+	return nil
+}
+
+// checkPath returns the absolute form of path if it is under one of the
+// allowed roots. Symbolic links are followed first, so they can't be used to
+// escape a root.
+func (i *Interpreter) checkPath(function string, path string) (string, *RuntimeError) {
+	if len(i.fsRoots) == 0 {
+		return "", &RuntimeError{Message: fmt.Sprintf("fs.%v(): file system access is disabled; allow it with --allow-fs.", function)}
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fsError(function, path, err)
+	}
+	resolved, err := resolveLinks(abs)
+	if err != nil {
+		return "", fsError(function, path, err)
+	}
+	for _, root := range i.fsRoots {
+		if rel, err := filepath.Rel(root, resolved); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return abs, nil
+		}
+	}
+	return "", &RuntimeError{Message: fmt.Sprintf("fs.%v(): access to '%v' is outside the allowed directories.", function, path)}
+}
+
+// maxLinks bounds the symbolic links resolveLinks follows, to stop on loops.
+const maxLinks = 255
+
+// resolveLinks follows the symbolic links in the absolute path one component
+// at a time. Unlike filepath.EvalSymlinks it also follows links whose target
+// doesn't exist yet, since writing through one creates the target. Components
+// that don't exist are kept as they are.
+func resolveLinks(path string) (string, error) {
+	separator := string(filepath.Separator)
+	root := filepath.VolumeName(path) + separator
+	resolved := root
+	rest := strings.Split(strings.TrimPrefix(path, root), separator)
+	for links := 0; len(rest) > 0; {
+		name := rest[0]
+		rest = rest[1:]
+		switch name {
+		case "", ".":
+			continue
+		case "..":
+			resolved = filepath.Dir(resolved)
+			continue
+		}
+
+		next := filepath.Join(resolved, name)
+		info, err := os.Lstat(next)
+		if err != nil || info.Mode()&fs.ModeSymlink == 0 {
+			resolved = next
+			continue
+		}
+		if links++; links > maxLinks {
+			return "", fmt.Errorf("too many levels of symbolic links")
+		}
+		target, err := os.Readlink(next)
+		if err != nil {
+			return "", err
+		}
+		if filepath.IsAbs(target) {
+			resolved = filepath.VolumeName(target) + separator
+			target = strings.TrimPrefix(target, resolved)
+		}
+		rest = append(strings.Split(target, separator), rest...)
+	}
+	return resolved, nil
+}
+
+// fsError turns an error from the os package into a runtime error.
+func fsError(function string, path string, err error) *RuntimeError {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		err = pathErr.Err
+	}
+	return &RuntimeError{Message: fmt.Sprintf("fs.%v(): '%v': %v.", function, path, err)}
+}
+
+func newFSModule(i *Interpreter) *LoxModule {
+	m := &LoxModule{name: "fs", members: make(map[string]interface{})}
+
+	// define adds a function whose first argument is a path, checked against
+	// the allowed roots before fn sees it.
+	define := func(name string, arity int, fn func(path string, arguments []interface{}) (interface{}, error)) {
+		m.define(name, arity, arity, func(arguments []interface{}) (interface{}, *RuntimeError) {
+			path, err := stringArgument("fs."+name, arguments[0])
+			if err != nil {
+				return nil, err
+			}
+			abs, err := i.checkPath(name, path)
+			if err != nil {
+				return nil, err
+			}
+			value, osErr := fn(abs, arguments[1:])
+			if osErr != nil {
+				if runtimeErr, ok := osErr.(*RuntimeError); ok {
+					return nil, runtimeErr
+				}
+				return nil, fsError(name, path, osErr)
+			}
+			return value, nil
+		})
+	}
+
+	define("readFile", 1, func(path string, arguments []interface{}) (interface{}, error) {
+		content, err := os.ReadFile(path)
+		return string(content), err
+	})
+	define("writeFile", 2, func(path string, arguments []interface{}) (interface{}, error) {
+		content, err := stringArgument("fs.writeFile", arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		return nil, os.WriteFile(path, []byte(content), 0o644)
+	})
+	define("appendFile", 2, func(path string, arguments []interface{}) (interface{}, error) {
+		content, err := stringArgument("fs.appendFile", arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		file, osErr := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if osErr != nil {
+			return nil, osErr
+		}
+		defer file.Close()
+		_, osErr = file.WriteString(content)
+		return nil, osErr
+	})
+	define("exists", 1, func(path string, arguments []interface{}) (interface{}, error) {
+		_, err := os.Stat(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return err == nil, err
+	})
+	define("listDir", 1, func(path string, arguments []interface{}) (interface{}, error) {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		slices.Sort(names)
+		return stringList(names), nil
+	})
+	define("mkdir", 1, func(path string, arguments []interface{}) (interface{}, error) {
+		return nil, os.MkdirAll(path, 0o755)
+	})
+	define("remove", 1, func(path string, arguments []interface{}) (interface{}, error) {
+		return nil, os.Remove(path)
+	})
+	define("stat", 1, func(path string, arguments []interface{}) (interface{}, error) {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		return &LoxFileInfo{info}, nil
+	})
+	define("open", 1, func(path string, arguments []interface{}) (interface{}, error) {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		return &LoxLineReader{file: file, reader: bufio.NewReader(file)}, nil
+	})
+
+	return m
+}
+
+// LoxFileInfo is the result of fs.stat.
+type LoxFileInfo struct {
+	info fs.FileInfo
+}
+
+func (f *LoxFileInfo) toString() string {
+	return "<stat " + f.info.Name() + ">"
+}
+
+func (f *LoxFileInfo) get(name Token) (interface{}, *RuntimeError) {
+	switch name.Lexeme {
+	case "name":
+		return f.info.Name(), nil
+	case "size":
+		return float64(f.info.Size()), nil
+	case "isDir":
+		return f.info.IsDir(), nil
+	case "modified":
+		// Seconds since the epoch, like clock().
+		return float64(f.info.ModTime().UnixMilli()) / 1000.0, nil
+	}
+	return nil, &RuntimeError{Token: name, Message: fmt.Sprintf("Undefined property '%v'.", name.Lexeme)}
+}
+
+// LoxLineReader reads a file a line at a time, as returned by fs.open.
+type LoxLineReader struct {
+	file   *os.File
+	reader *bufio.Reader
+	closed bool
+}
+
+func (r *LoxLineReader) toString() string {
+	return "<reader " + r.file.Name() + ">"
+}
+
+func (r *LoxLineReader) get(name Token) (interface{}, *RuntimeError) {
+	switch name.Lexeme {
+	case "readLine":
+		// readLine returns the next line without its line ending, or nil at the
+		// end of the file.
+		return &NativeFunction{"readLine", 0, 0, func(arguments []interface{}) (interface{}, *RuntimeError) {
+			if r.closed {
+				return nil, &RuntimeError{Message: "Can't read from a closed file."}
+			}
+			return readLine(r.reader, r.file.Name())
+		}}, nil
+	case "close":
+		return &NativeFunction{"close", 0, 0, func(arguments []interface{}) (interface{}, *RuntimeError) {
+			if !r.closed {
+				r.closed = true
+				r.file.Close()
+			}
+			return nil, nil
+		}}, nil
+	}
+	return nil, &RuntimeError{Token: name, Message: fmt.Sprintf("Undefined property '%v'.", name.Lexeme)}
+}
+
+// readLine reads a line from reader, without its line ending, or nil at EOF.
+func readLine(reader *bufio.Reader, source string) (interface{}, *RuntimeError) {
+	line, err := reader.ReadString('\n')
+	if err == io.EOF && line == "" {
+		return nil, nil
+	}
+	if err != nil && err != io.EOF {
+		return nil, &RuntimeError{Message: fmt.Sprintf("Can't read from %v: %v.", source, err)}
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r"), nil
+}
@@ -0,0 +1,76 @@
+package lox
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFS(t *testing.T) {
+	dir := t.TempDir()
+	source := strings.ReplaceAll(`
+var path = "DIR/notes.txt";
+fs.writeFile(path, "one
+two
+");
+fs.appendFile(path, "three");
+print fs.readFile(path);
+print fs.exists(path);
+print fs.stat(path).size;
+fs.mkdir("DIR/sub");
+print fs.listDir("DIR");
+var reader = fs.open(path);
+var line = reader.readLine();
+while (line != nil) { print line; line = reader.readLine(); }
+reader.close();
+fs.remove(path);
+print fs.exists(path);
+`, "DIR", dir)
+
+	stdout, stderr, _ := runScript(t, source, allowFSEnv+"="+dir)
+	want := "one\ntwo\nthree\ntrue\n13\n[notes.txt, sub]\none\ntwo\nthree\nfalse\n"
+	if stdout != want || stderr != "" {
+		t.Errorf("got %q, stderr %q; want %q", stdout, stderr, want)
+	}
+}
+
+func TestFSSandbox(t *testing.T) {
+	dir := t.TempDir()
+	outside := t.TempDir()
+	rel, err := filepath.Rel(dir, outside)
+	if err != nil {
+		t.Fatal(err)
+	}
+	links := map[string]string{
+		"link":     outside,
+		"dangling": filepath.Join(outside, "new"),
+		"relative": filepath.Join(rel, "new"),
+		"inside":   filepath.Join(dir, "target"),
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(dir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, stderr, _ := runScript(t, `fs.readFile("x");`)
+	if !strings.Contains(stderr, "fs.readFile(): file system access is disabled; allow it with --allow-fs.") {
+		t.Errorf("got error output %q, want access disabled", stderr)
+	}
+
+	for _, path := range []string{outside + "/x", dir + "/../x", dir + "/link/x", dir + "/dangling", dir + "/relative"} {
+		_, stderr, _ := runScript(t, `fs.writeFile("`+path+`", "x");`, allowFSEnv+"="+dir)
+		if !strings.Contains(stderr, "is outside the allowed directories.") {
+			t.Errorf("writing %v: got error output %q, want access denied", path, stderr)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(outside, "new")); err == nil {
+		t.Errorf("a link to a missing file let a script create it outside the allowed directories")
+	}
+
+	_, stderr, _ = runScript(t, `fs.writeFile("`+dir+`/inside", "x");`, allowFSEnv+"="+dir)
+	if stderr != "" {
+		t.Errorf("writing through a link to a missing file inside the directory: got error output %q", stderr)
+	}
+}
@@ -0,0 +1,65 @@
+package lox
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+)
+
+// SetStdin makes scripts and the REPL read input from r instead of os.Stdin.
+func (i *Interpreter) SetStdin(r io.Reader) {
+	i.stdin = bufio.NewReader(r)
+}
+
+// readInput runs read on stdin without holding the interpreter lock, since it
+// may block for a long time. Tasks take turns to read.
+func (i *Interpreter) readInput(read func(*bufio.Reader)) *RuntimeError {
+	return i.unlocked(func() {
+		i.stdinLock.Lock()
+		defer i.stdinLock.Unlock()
+		read(i.stdin)
+	})
+}
+
+// defineInputNatives registers input, readLine and readAll.
+func defineInputNatives(i *Interpreter) {
+	i.SetStdin(os.Stdin)
+
+	// input shows a prompt and returns the line typed, or nil at the end of
+	// the input.
+	input := func(arguments []interface{}) (interface{}, *RuntimeError) {
+		if len(arguments) == 1 {
+			prompt, err := stringify(arguments[0])
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprint(i.stdout, prompt)
+		}
+		var line interface{}
+		var readErr *RuntimeError
+		if err := i.readInput(func(r *bufio.Reader) {
+			line, readErr = readLine(r, "standard input")
+		}); err != nil {
+			return nil, err
+		}
+		return line, readErr
+	}
+	i.globals.define("input", &NativeFunction{"input", 0, 1, input})
+	i.globals.define("readLine", &NativeFunction{"readLine", 0, 0, input})
+
+	// readAll returns the rest of the input.
+	i.globals.define("readAll", &NativeFunction{"readAll", 0, 0, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		var content []byte
+		var readErr error
+		if err := i.readInput(func(r *bufio.Reader) {
+			content, readErr = io.ReadAll(r)
+		}); err != nil {
+			return nil, err
+		}
+		if readErr != nil {
+			return nil, &RuntimeError{Message: fmt.Sprintf("Can't read from standard input: %v.", readErr)}
+		}
+		return string(content), nil
+	}})
+}
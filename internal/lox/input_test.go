@@ -0,0 +1,35 @@
+package lox
+
+import "testing"
+
+func TestInput(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		stdin  string
+		want   string
+	}{
+		{"input", `var name = input("name? "); print "hi " + name; print readLine(); print readLine();`, "bob\nx\n",
+			"name? hi bob\nx\nnil\n"},
+		{"readAll", `print readLine(); print readAll();`, "one\ntwo\nthree", "one\ntwo\nthree\n"},
+		{"line endings", `print readLine(); print readLine(); print readLine();`, "a\r\nb", "a\nb\nnil\n"},
+		{"readAll at end", `print readAll(); print readAll() == ""; print readLine();`, "x\ny", "x\ny\ntrue\nnil\n"},
+		{"prompt", `print input(42);`, "a\n", "42a\n"},
+		{"tasks", `
+fun read() { return readLine(); }
+var t = spawn read();
+var mine = readLine();
+var theirs = t.join();
+// Either may read first, but each line goes to exactly one of them.
+print mine != theirs and (mine == "a" or mine == "b") and (theirs == "a" or theirs == "b");`, "a\nb\n", "true\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stdout, stderr, status := runScriptWithInput(t, test.source, test.stdin)
+			if stdout != test.want || stderr != "" || status != 0 {
+				t.Errorf("got %q, stderr %q, status %d; want %q", stdout, stderr, status, test.want)
+			}
+		})
+	}
+}
@@ -5,24 +5,60 @@ import "fmt"
 type LoxInstance struct {
 	class  *LoxClass
 	fields map[string]interface{}
+	// decorated caches the decorated form of each bound method.
+	decorated map[*LoxFunction]interface{}
 }
 
 func (li *LoxInstance) toString() string {
-	return li.class.name + " instance"
+	return "<" + li.class.name + " instance>"
 }
 
 func (li *LoxInstance) get(name Token) (interface{}, *RuntimeError) {
+	// Getters shadow raw fields so computed properties always run.
+	if getter := li.class.findGetter(name.Lexeme); getter != nil {
+		return getter.bind(li).call(nil)
+	}
 	if value, exists := li.fields[name.Lexeme]; exists {
 		return value, nil
 	}
 	method := li.class.findMethod(name.Lexeme)
 	if method != nil {
+		return li.bindMethod(method)
+	}
+
+	return nil, &RuntimeError{Token: name, Message: fmt.Sprintf("Undefined property '%v'.", name.Lexeme)}
+}
+
+// bindMethod binds method to the instance and applies its decorators. They run
+// once per instance, so a decorator keeping state, such as a cache, keeps it
+// for as long as the instance lives.
+func (li *LoxInstance) bindMethod(method *LoxFunction) (interface{}, *RuntimeError) {
+	if len(method.decorators) == 0 {
 		return method.bind(li), nil
 	}
+	if value, exists := li.decorated[method]; exists {
+		return value, nil
+	}
 
-	return nil, &RuntimeError{name, fmt.Sprintf("Undefined property '%v'.", name.Lexeme)}
+	value, err := decorate(method.decorators, method.bind(li), *method.declaration.name)
+	if err != nil {
+		return nil, err
+	}
+	if li.decorated == nil {
+		li.decorated = make(map[*LoxFunction]interface{})
+	}
+	li.decorated[method] = value
+	return value, nil
 }
 
-func (li *LoxInstance) set(name Token, value interface{}) {
+func (li *LoxInstance) set(name Token, value interface{}) *RuntimeError {
+	if setter := li.class.findSetter(name.Lexeme); setter != nil {
+		_, err := setter.bind(li).call([]interface{}{value})
+		return err
+	}
+	if li.class.findGetter(name.Lexeme) != nil {
+		return &RuntimeError{Token: name, Message: fmt.Sprintf("Can't assign to read-only property '%v'.", name.Lexeme)}
+	}
 	li.fields[name.Lexeme] = value
+	return nil
 }
@@ -1,28 +1,128 @@
 package lox
 
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+)
+
 type Interpreter struct {
 	globals     *Environment
 	environment *Environment
 	locals      map[Expr]int
 	hadError    bool
+	loop        *EventLoop
+	// coroutine is the async function currently running, if any.
+	coroutine *coroutine
+	// frames is the Lox call stack, kept for stack traces.
+	frames []frame
+	// lock is held by whichever task is running Lox code; see task.go.
+	lock sync.Mutex
+	// changed is signalled whenever a task finishes or a channel changes.
+	changed *sync.Cond
+	// running counts the tasks still running, including the main one, and
+	// blocked the ones waiting on changed.
+	running int
+	blocked int
+	// deadlocks counts the deadlocks found, so waiting tasks can tell they
+	// were woken by one.
+	deadlocks int
+	tasks     []*LoxTask
+	// fsRoots are the directories the fs module may access; see AllowFS.
+	fsRoots []string
+	clock   Clock
+	// started is when the interpreter started, for time.monotonic().
+	started time.Time
+	// random backs the random module; see Seed.
+	random *rand.Rand
+	// hadRuntimeError is set once a runtime error has been reported.
+	hadRuntimeError bool
+	// exited is set by exit(), which stops the program with exitStatus.
+	exited     bool
+	exitStatus int
+	// stdin is where scripts and the REPL read input; see SetStdin.
+	stdin     *bufio.Reader
+	stdinLock sync.Mutex
+	// stdout and stderr are where output and error reports go; see SetOutput.
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// frame records a call in progress: the function called and the line it was
+// called from.
+type frame struct {
+	function string
+	line     int
 }
 
-var interpreter = NewInterpreter()
+var interpreter *Interpreter
+
+// DefaultInterpreter returns the interpreter RunFile and RunPrompt run code on,
+// so the host can configure it first.
+func DefaultInterpreter() *Interpreter {
+	return interpreter
+}
+
+// init creates the interpreter at startup rather than in a variable
+// initializer, since natives refer back to it.
+func init() {
+	interpreter = NewInterpreter()
+}
 
 func NewInterpreter() *Interpreter {
 	i := Interpreter{}
 	i.globals = NewEnvironment()
 	i.environment = i.globals
 	i.locals = make(map[Expr]int)
+	i.loop = NewEventLoop(&i)
+	i.SetClock(systemClock{})
+	i.SetOutput(os.Stdout, os.Stderr)
+	i.Seed(i.clock.Now().UnixNano())
+	i.changed = sync.NewCond(&i.lock)
+	i.running = 1
 
-	i.globals.define("clock", ClockFunction{})
+	i.globals.define("clock", ClockFunction{&i})
+	i.globals.define("math", newMathModule())
+	i.globals.define("fs", newFSModule(&i))
+	i.globals.define("json", newJSONModule())
+	i.globals.define("time", newTimeModule(&i))
+	i.globals.define("random", newRandomModule(&i))
+	i.globals.define("List", &NativeFunction{"List", 0, variadic, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		return NewList(append([]interface{}{}, arguments...)), nil
+	}})
+	i.globals.define("Map", &NativeFunction{"Map", 0, 0, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		return NewMap(), nil
+	}})
+	i.globals.define("repr", &NativeFunction{"repr", 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		return repr(arguments[0], make(map[interface{}]bool)), nil
+	}})
+	defineEventLoopNatives(&i)
+	defineChannelNatives(&i)
+	defineReflectionNatives(&i)
+	defineStringNatives(&i)
+	defineRegexNatives(&i)
+	defineProcessNatives(&i)
+	defineInputNatives(&i)
+	defineFormatNatives(&i)
 
 	return &i
 }
 
+// SetOutput sends what scripts print to stdout, and error reports to stderr.
+func (i *Interpreter) SetOutput(stdout, stderr io.Writer) {
+	i.stdout, i.stderr = stdout, stderr
+}
+
 func (i *Interpreter) interpret(statements []Stmt) {
 	for _, statement := range statements {
 		err := execute(statement)
+		if i.exited {
+			return
+		}
 		if err != nil {
 			handleRuntimeError(err)
 		}
@@ -32,3 +132,15 @@ func (i *Interpreter) interpret(statements []Stmt) {
 func (i *Interpreter) resolve(expr Expr, depth int) {
 	i.locals[expr] = depth
 }
+
+// stackTrace describes the call stack at the point err was raised, innermost
+// call first.
+func (i *Interpreter) stackTrace(err *RuntimeError) []string {
+	var trace []string
+	line := err.Token.Line
+	for j := len(i.frames) - 1; j >= 0; j-- {
+		trace = append(trace, fmt.Sprintf("[line %d] in %s", line, i.frames[j].function))
+		line = i.frames[j].line
+	}
+	return append(trace, fmt.Sprintf("[line %d] in script", line))
+}
@@ -0,0 +1,235 @@
+package lox
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+)
+
+func newJSONModule() *LoxModule {
+	m := &LoxModule{name: "json", members: make(map[string]interface{})}
+
+	m.define("parse", 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		text, err := stringArgument("json.parse", arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		return parseJSON(text)
+	})
+
+	// stringify takes an optional indent, as a number of spaces or a string.
+	m.define("stringify", 1, 2, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		encoder := jsonEncoder{seen: make(map[interface{}]bool)}
+		if len(arguments) == 2 {
+			switch indent := arguments[1].(type) {
+			case nil:
+			case float64:
+				encoder.indent = strings.Repeat(" ", max(int(indent), 0))
+			case string:
+				encoder.indent = indent
+			default:
+				return nil, &RuntimeError{Message: "json.stringify() indent must be a number or a string."}
+			}
+		}
+		if err := encoder.encode(arguments[0], 0); err != nil {
+			return nil, err
+		}
+		return encoder.out.String(), nil
+	})
+
+	return m
+}
+
+// parseJSON converts JSON text to Lox values. Objects become maps that keep
+// the order of their keys.
+func parseJSON(text string) (interface{}, *RuntimeError) {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	value, err := decodeJSON(decoder)
+	if err == nil {
+		if _, err = decoder.Token(); err == io.EOF {
+			return value, nil
+		} else if err == nil {
+			err = errors.New("unexpected data after the value")
+		}
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return nil, &RuntimeError{Message: fmt.Sprintf("json.parse(): invalid JSON at offset %d: %v.", syntaxErr.Offset, err)}
+	}
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = errors.New("unexpected end of input")
+	}
+	return nil, &RuntimeError{Message: fmt.Sprintf("json.parse(): invalid JSON at offset %d: %v.", decoder.InputOffset(), err)}
+}
+
+func decodeJSON(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := token.(json.Delim)
+	if !ok {
+		// Numbers are already float64, like Lox numbers.
+		return token, nil
+	}
+
+	if delim == '[' {
+		list := NewList(nil)
+		for decoder.More() {
+			element, err := decodeJSON(decoder)
+			if err != nil {
+				return nil, err
+			}
+			list.elements = append(list.elements, element)
+		}
+		_, err = decoder.Token()
+		return list, err
+	}
+
+	object := NewMap()
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		value, err := decodeJSON(decoder)
+		if err != nil {
+			return nil, err
+		}
+		object.set(key, value)
+	}
+	_, err = decoder.Token()
+	return object, err
+}
+
+// jsonEncoder writes Lox values as JSON. Instances are written as their
+// toJSON() result when they have one, or as their fields.
+type jsonEncoder struct {
+	indent string
+	// seen holds the values being written, to catch cycles.
+	seen map[interface{}]bool
+	out  strings.Builder
+}
+
+func (e *jsonEncoder) encode(value interface{}, depth int) *RuntimeError {
+	switch v := value.(type) {
+	case nil:
+		e.out.WriteString("null")
+	case bool:
+		fmt.Fprint(&e.out, v)
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return &RuntimeError{Message: fmt.Sprintf("json.stringify() can't represent %v.", plainString(v))}
+		}
+		number, _ := json.Marshal(v)
+		e.out.Write(number)
+	case string:
+		e.writeString(v)
+	case *LoxEnumValue:
+		e.writeString(v.name)
+	case *LoxList:
+		return e.container(v, "[", "]", len(v.elements), depth, func(i int) *RuntimeError {
+			return e.encode(v.elements[i], depth+1)
+		})
+	case *LoxMap:
+		return e.container(v, "{", "}", len(v.keys), depth, func(i int) *RuntimeError {
+			key, ok := v.keys[i].(string)
+			if !ok {
+				return &RuntimeError{Message: "json.stringify() needs map keys to be strings."}
+			}
+			e.member(key)
+			return e.encode(v.values[key], depth+1)
+		})
+	case *LoxInstance:
+		if method := v.class.findMethod("toJSON"); method != nil {
+			return e.toJSON(v, method, depth)
+		}
+		var names []string
+		for name := range v.fields {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		return e.container(v, "{", "}", len(names), depth, func(i int) *RuntimeError {
+			e.member(names[i])
+			return e.encode(v.fields[names[i]], depth+1)
+		})
+	default:
+		return &RuntimeError{Message: fmt.Sprintf("json.stringify() can't represent %v.", plainString(v))}
+	}
+	return nil
+}
+
+// container writes a list or object of n items, calling item to write each.
+func (e *jsonEncoder) container(value interface{}, open, close string, n int, depth int, item func(i int) *RuntimeError) *RuntimeError {
+	if e.seen[value] {
+		return &RuntimeError{Message: "json.stringify() can't represent a value that contains itself."}
+	}
+	e.seen[value] = true
+	defer delete(e.seen, value)
+
+	e.out.WriteString(open)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			e.out.WriteString(",")
+		}
+		e.newline(depth + 1)
+		if err := item(i); err != nil {
+			return err
+		}
+	}
+	if n > 0 {
+		e.newline(depth)
+	}
+	e.out.WriteString(close)
+	return nil
+}
+
+func (e *jsonEncoder) toJSON(instance *LoxInstance, method *LoxFunction, depth int) *RuntimeError {
+	if e.seen[instance] {
+		return &RuntimeError{Message: "json.stringify() can't represent a value that contains itself."}
+	}
+	e.seen[instance] = true
+	defer delete(e.seen, instance)
+
+	bound, err := instance.bindMethod(method)
+	if err != nil {
+		return err
+	}
+	function, ok := bound.(Callable)
+	if !ok {
+		return &RuntimeError{Token: *method.declaration.name, Message: "toJSON must be callable."}
+	}
+	value, err := function.call(nil)
+	if err != nil {
+		return err
+	}
+	return e.encode(value, depth)
+}
+
+func (e *jsonEncoder) member(key string) {
+	e.writeString(key)
+	e.out.WriteString(":")
+	if e.indent != "" {
+		e.out.WriteString(" ")
+	}
+}
+
+func (e *jsonEncoder) newline(depth int) {
+	if e.indent != "" {
+		e.out.WriteString("\n" + strings.Repeat(e.indent, depth))
+	}
+}
+
+func (e *jsonEncoder) writeString(s string) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+	e.out.Write(bytes.TrimSuffix(buffer.Bytes(), []byte("\n")))
+}
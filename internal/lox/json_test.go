@@ -0,0 +1,40 @@
+package lox
+
+import "testing"
+
+func TestJSON(t *testing.T) {
+	checkScripts(t, []scriptTest{
+		{name: "stringify", source: `
+var m = Map();
+m.set("a", List(1, 2.5, nil, true));
+m.set("b", Map());
+print json.stringify(m);
+print json.stringify(m, 2);`, want: `{"a":[1,2.5,null,true],"b":{}}
+{
+  "a": [
+    1,
+    2.5,
+    null,
+    true
+  ],
+  "b": {}
+}
+`},
+		{name: "non-string key", source: `var m = Map(); m.set(1, 2); json.stringify(m);`,
+			err: "json.stringify() needs map keys to be strings."},
+		{name: "cycle", source: `var l = List(); l.push(l); json.stringify(l);`,
+			err: "json.stringify() can't represent a value that contains itself."},
+		{name: "function", source: `json.stringify(clock);`,
+			err: "json.stringify() can't represent <native fn clock>."},
+		{name: "parse", source: `
+var v = json.parse("[1, 2, null, true, {}]");
+print v;
+print v[4];`, want: "[1, 2, nil, true, {}]\n{}\n"},
+		{name: "round trip", source: `
+var m = Map();
+m.set("x", List(1, "s"));
+print json.parse(json.stringify(m)).get("x");`, want: "[1, s]\n"},
+		{name: "invalid", source: `json.parse("[1, ");`,
+			err: "json.parse(): invalid JSON at offset 4: unexpected end of JSON input."},
+	})
+}
@@ -0,0 +1,65 @@
+package lox
+
+import "fmt"
+
+// LoxList is the runtime value of a Lox list.
+type LoxList struct {
+	elements []interface{}
+}
+
+func NewList(elements []interface{}) *LoxList {
+	return &LoxList{elements: elements}
+}
+
+func (l *LoxList) get(name Token) (interface{}, *RuntimeError) {
+	switch name.Lexeme {
+	case "length":
+		return float64(len(l.elements)), nil
+	case "push":
+		return &NativeFunction{"push", 1, variadic, func(arguments []interface{}) (interface{}, *RuntimeError) {
+			l.elements = append(l.elements, arguments...)
+			return float64(len(l.elements)), nil
+		}}, nil
+	case "pop":
+		return &NativeFunction{"pop", 0, 0, func(arguments []interface{}) (interface{}, *RuntimeError) {
+			if len(l.elements) == 0 {
+				return nil, &RuntimeError{Token: name, Message: "Can't pop from an empty list."}
+			}
+			last := l.elements[len(l.elements)-1]
+			l.elements = l.elements[:len(l.elements)-1]
+			return last, nil
+		}}, nil
+	case "get":
+		return &NativeFunction{"get", 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+			i, err := l.index(name, arguments[0])
+			if err != nil {
+				return nil, err
+			}
+			return l.elements[i], nil
+		}}, nil
+	case "set":
+		return &NativeFunction{"set", 2, 2, func(arguments []interface{}) (interface{}, *RuntimeError) {
+			i, err := l.index(name, arguments[0])
+			if err != nil {
+				return nil, err
+			}
+			l.elements[i] = arguments[1]
+			return arguments[1], nil
+		}}, nil
+	}
+
+	return nil, &RuntimeError{Token: name, Message: fmt.Sprintf("Undefined property '%v'.", name.Lexeme)}
+}
+
+// index converts a Lox value to a position in the list.
+func (l *LoxList) index(name Token, value interface{}) (int, *RuntimeError) {
+	number, ok := value.(float64)
+	if !ok || number != float64(int(number)) {
+		return 0, &RuntimeError{Token: name, Message: "List index must be an integer."}
+	}
+	i := int(number)
+	if i < 0 || i >= len(l.elements) {
+		return 0, &RuntimeError{Token: name, Message: "List index out of range."}
+	}
+	return i, nil
+}
@@ -1,7 +1,6 @@
 package lox
 
 import (
-	"bufio"
 	"errors"
 	"fmt"
 	"io"
@@ -10,7 +9,9 @@ import (
 	"strings"
 )
 
-func RunFile(path string) {
+// RunFile runs a script and returns the status to exit with: the one passed
+// to exit(), 65 for a compile error, 70 for a runtime error, or 0.
+func RunFile(path string) int {
 	file, err := os.ReadFile(path)
 	if err != nil {
 		log.Fatal(err)
@@ -21,26 +22,36 @@ func RunFile(path string) {
 		Line:   1,
 	}
 
+	interpreter.lock.Lock()
 	err = run(&scanner)
-	if err != nil {
-		if _, ok := err.(*RuntimeError); ok {
-			os.Exit(70)
-		}
-		os.Exit(65)
+	interpreter.loop.run()
+	interpreter.waitForTasks()
+	interpreter.lock.Unlock()
+	switch {
+	case interpreter.exited:
+		return interpreter.exitStatus
+	case err != nil:
+		return 65
+	case interpreter.hadRuntimeError:
+		return 70
 	}
+	return 0
 }
 
-func RunPrompt() {
-	reader := bufio.NewReader(os.Stdin)
+// RunPrompt runs lines from standard input until it ends or a line calls
+// exit(), and returns the status to exit with.
+func RunPrompt() int {
 	scanner := Scanner{Line: 1}
 	for {
 		if !scanner.InBlockComment {
-			fmt.Print("> ")
+			fmt.Fprint(interpreter.stdout, "> ")
 		}
-		line, err := reader.ReadString('\n')
+		interpreter.stdinLock.Lock()
+		line, err := interpreter.stdin.ReadString('\n')
+		interpreter.stdinLock.Unlock()
 		if err != nil {
 			if err == io.EOF {
-				fmt.Println("\nbye!")
+				fmt.Fprintln(interpreter.stdout, "\nbye!")
 				break
 			}
 			log.Fatal(err)
@@ -48,9 +59,19 @@ func RunPrompt() {
 		scanner.Source = strings.TrimSpace(line) // Update source for the new line
 		scanner.Current = 0                      // Reset current position for new input
 		scanner.Tokens = nil                     // Clear previous tokens
+		interpreter.hadError = false             // Errors don't carry over to the next line
 
+		// Spawned tasks run while we wait for input, so take the interpreter back.
+		interpreter.lock.Lock()
+		interpreter.restoreState(taskState{environment: interpreter.globals})
 		run(&scanner)
+		exited := interpreter.exited
+		interpreter.lock.Unlock()
+		if exited {
+			return interpreter.exitStatus
+		}
 	}
+	return 0
 }
 
 func run(scanner *Scanner) error {
@@ -94,5 +115,5 @@ func reportTokenError(token Token, message string) {
 }
 
 func report(lint int, where string, message string) {
-	fmt.Fprintf(os.Stderr, "[line %d] Error%s: %s\n", lint, where, message)
+	fmt.Fprintf(interpreter.stderr, "[line %d] Error%s: %s\n", lint, where, message)
 }
@@ -0,0 +1,132 @@
+package lox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// scriptEnv names the variable that makes the test binary run a script
+// instead of the tests, the way the gravlax binary would. Running each script
+// in its own process gives it a fresh interpreter and lets it exit.
+const scriptEnv = "GRAVLAX_TEST_SCRIPT"
+
+// allowFSEnv names a directory the script may use, as with --allow-fs.
+const allowFSEnv = "GRAVLAX_TEST_ALLOW_FS"
+
+// seedEnv holds a seed for the random module, as with --seed.
+const seedEnv = "GRAVLAX_TEST_SEED"
+
+// argsEnv holds the script's arguments, separated by spaces.
+const argsEnv = "GRAVLAX_TEST_ARGS"
+
+// clockEnv holds an RFC 3339 time to start a fakeClock at.
+const clockEnv = "GRAVLAX_TEST_CLOCK"
+
+func TestMain(m *testing.M) {
+	if path := os.Getenv(scriptEnv); path != "" {
+		if dir := os.Getenv(allowFSEnv); dir != "" {
+			if err := interpreter.AllowFS(dir); err != nil {
+				log.Fatal(err)
+			}
+		}
+		if seed := os.Getenv(seedEnv); seed != "" {
+			n, err := strconv.ParseInt(seed, 10, 64)
+			if err != nil {
+				log.Fatal(err)
+			}
+			interpreter.Seed(n)
+		}
+		if start := os.Getenv(clockEnv); start != "" {
+			now, err := time.Parse(time.RFC3339, start)
+			if err != nil {
+				log.Fatal(err)
+			}
+			interpreter.SetClock(&fakeClock{now: now})
+		}
+		interpreter.SetArgs(strings.Fields(os.Getenv(argsEnv)))
+		os.Exit(RunFile(path))
+	}
+	os.Exit(m.Run())
+}
+
+// runScript runs source in a child process and returns what it wrote to
+// stdout and stderr, and its exit status. env adds to the child's environment.
+func runScript(t *testing.T, source string, env ...string) (string, string, int) {
+	t.Helper()
+	return runScriptWithInput(t, source, "", env...)
+}
+
+// runScriptWithInput is runScript with stdin as the script's standard input.
+func runScriptWithInput(t *testing.T, source string, stdin string, env ...string) (string, string, int) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "script.lox")
+	if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, os.Args[0])
+	// Under the race detector, don't let each script wait a second to exit.
+	cmd.Env = append(os.Environ(), "GORACE=atexit_sleep_ms=0")
+	cmd.Env = append(append(cmd.Env, env...), scriptEnv+"="+path)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if ctx.Err() != nil {
+		t.Fatalf("script didn't finish: %v", ctx.Err())
+	} else if err != nil && !errors.As(err, &exitErr) {
+		t.Fatal(err)
+	}
+	return stdout.String(), stderr.String(), cmd.ProcessState.ExitCode()
+}
+
+// fakeClock only moves when something sleeps.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// scriptTest describes a script and what running it should print. err is a
+// substring of the expected error output; when empty, none is expected.
+type scriptTest struct {
+	name   string
+	source string
+	want   string
+	err    string
+}
+
+func checkScripts(t *testing.T, tests []scriptTest) {
+	t.Helper()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stdout, stderr, _ := runScript(t, test.source)
+			if stdout != test.want {
+				t.Errorf("got output %q, want %q", stdout, test.want)
+			}
+			if test.err == "" && stderr != "" || !strings.Contains(stderr, test.err) {
+				t.Errorf("got error output %q, want %q", stderr, test.err)
+			}
+		})
+	}
+}
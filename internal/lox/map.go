@@ -0,0 +1,85 @@
+package lox
+
+import (
+	"fmt"
+	"math"
+)
+
+// LoxMap is the runtime value of a Lox map. Keys can be any value, and are
+// kept in the order they were first set.
+type LoxMap struct {
+	keys   []interface{}
+	values map[interface{}]interface{}
+}
+
+func NewMap() *LoxMap {
+	return &LoxMap{values: make(map[interface{}]interface{})}
+}
+
+func (m *LoxMap) set(key, value interface{}) {
+	if _, exists := m.values[key]; !exists {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+func (m *LoxMap) get(name Token) (interface{}, *RuntimeError) {
+	switch name.Lexeme {
+	case "length":
+		return float64(len(m.keys)), nil
+	case "get":
+		// get returns nil for a missing key, or the default when one is given.
+		return &NativeFunction{"get", 1, 2, func(arguments []interface{}) (interface{}, *RuntimeError) {
+			if value, exists := m.values[arguments[0]]; exists {
+				return value, nil
+			}
+			if len(arguments) == 2 {
+				return arguments[1], nil
+			}
+			return nil, nil
+		}}, nil
+	case "set":
+		return &NativeFunction{"set", 2, 2, func(arguments []interface{}) (interface{}, *RuntimeError) {
+			// NaN never equals itself, so a NaN key could never be read back.
+			if key, ok := arguments[0].(float64); ok && math.IsNaN(key) {
+				return nil, &RuntimeError{Message: "set() key can't be NaN."}
+			}
+			m.set(arguments[0], arguments[1])
+			return arguments[1], nil
+		}}, nil
+	case "has":
+		return &NativeFunction{"has", 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+			_, exists := m.values[arguments[0]]
+			return exists, nil
+		}}, nil
+	case "delete":
+		// delete returns whether the key was present.
+		return &NativeFunction{"delete", 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+			if _, exists := m.values[arguments[0]]; !exists {
+				return false, nil
+			}
+			delete(m.values, arguments[0])
+			for i, key := range m.keys {
+				if key == arguments[0] {
+					m.keys = append(m.keys[:i], m.keys[i+1:]...)
+					break
+				}
+			}
+			return true, nil
+		}}, nil
+	case "keys":
+		return &NativeFunction{"keys", 0, 0, func(arguments []interface{}) (interface{}, *RuntimeError) {
+			return NewList(append([]interface{}{}, m.keys...)), nil
+		}}, nil
+	case "values":
+		return &NativeFunction{"values", 0, 0, func(arguments []interface{}) (interface{}, *RuntimeError) {
+			values := make([]interface{}, len(m.keys))
+			for i, key := range m.keys {
+				values[i] = m.values[key]
+			}
+			return NewList(values), nil
+		}}, nil
+	}
+
+	return nil, &RuntimeError{Token: name, Message: fmt.Sprintf("Undefined property '%v'.", name.Lexeme)}
+}
@@ -0,0 +1,31 @@
+package lox
+
+import "testing"
+
+func TestMap(t *testing.T) {
+	checkScripts(t, []scriptTest{
+		{name: "methods", source: `
+var m = Map();
+m.set("b", 1);
+m.set("a", List(1, 2));
+m.set(3, true);
+print m;
+print m.length;
+print m.get("b");
+print m.get("z");
+print m.get("z", 0);
+print m.has("a");
+print m.keys();
+print m.values();
+m.delete("b");
+print m;`, want: "{b: 1, a: [1, 2], 3: true}\n3\n1\nnil\n0\ntrue\n[b, a, 3]\n[1, [1, 2], true]\n{a: [1, 2], 3: true}\n"},
+		{name: "insertion order", source: `
+var m = Map();
+m.set("x", 1);
+m.set("y", 2);
+m.set("x", 3);
+print m;`, want: "{x: 3, y: 2}\n"},
+		{name: "NaN key", source: `var m = Map(); m.set(0/0, 1); print m.length;`, want: "0\n", err: "set() key can't be NaN."},
+		{name: "index", source: `var m = Map(); m.set("k", "v"); print m["k"];`, want: "v\n"},
+	})
+}
@@ -0,0 +1,110 @@
+package lox
+
+import (
+	"fmt"
+	"math"
+)
+
+func newMathModule() *LoxModule {
+	m := &LoxModule{name: "math", members: map[string]interface{}{
+		"PI":  math.Pi,
+		"E":   math.E,
+		"inf": math.Inf(1),
+		"nan": math.NaN(),
+	}}
+
+	// Functions of one number. domain, when set, reports whether an argument
+	// is valid.
+	unary := func(name string, fn func(float64) float64, domain func(float64) bool, expected string) {
+		m.define(name, 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+			x, err := numberArguments("math."+name, arguments)
+			if err != nil {
+				return nil, err
+			}
+			if domain != nil && !domain(x[0]) {
+				return nil, domainError(name, expected, x[0])
+			}
+			return fn(x[0]), nil
+		})
+	}
+	nonNegative := func(x float64) bool { return x >= 0 || math.IsNaN(x) }
+	positive := func(x float64) bool { return x > 0 || math.IsNaN(x) }
+	unit := func(x float64) bool { return (x >= -1 && x <= 1) || math.IsNaN(x) }
+
+	unary("sqrt", math.Sqrt, nonNegative, "a non-negative number")
+	unary("abs", math.Abs, nil, "")
+	unary("floor", math.Floor, nil, "")
+	unary("ceil", math.Ceil, nil, "")
+	unary("round", math.Round, nil, "")
+	unary("trunc", math.Trunc, nil, "")
+	unary("sin", math.Sin, nil, "")
+	unary("cos", math.Cos, nil, "")
+	unary("tan", math.Tan, nil, "")
+	unary("asin", math.Asin, unit, "a number between -1 and 1")
+	unary("acos", math.Acos, unit, "a number between -1 and 1")
+	unary("atan", math.Atan, nil, "")
+	unary("exp", math.Exp, nil, "")
+	unary("log", math.Log, positive, "a positive number")
+	unary("log2", math.Log2, positive, "a positive number")
+	unary("log10", math.Log10, positive, "a positive number")
+
+	m.define("atan2", 2, 2, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		x, err := numberArguments("math.atan2", arguments)
+		if err != nil {
+			return nil, err
+		}
+		return math.Atan2(x[0], x[1]), nil
+	})
+	m.define("pow", 2, 2, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		x, err := numberArguments("math.pow", arguments)
+		if err != nil {
+			return nil, err
+		}
+		if x[0] < 0 && x[1] != math.Trunc(x[1]) && !math.IsInf(x[1], 0) {
+			return nil, &RuntimeError{Message: fmt.Sprintf("math.pow() can't raise negative number %v to fractional power %v.", plainString(x[0]), plainString(x[1]))}
+		}
+		return math.Pow(x[0], x[1]), nil
+	})
+	m.define("min", 1, variadic, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		x, err := numberArguments("math.min", arguments)
+		if err != nil {
+			return nil, err
+		}
+		result := x[0]
+		for _, n := range x[1:] {
+			result = math.Min(result, n)
+		}
+		return result, nil
+	})
+	m.define("max", 1, variadic, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		x, err := numberArguments("math.max", arguments)
+		if err != nil {
+			return nil, err
+		}
+		result := x[0]
+		for _, n := range x[1:] {
+			result = math.Max(result, n)
+		}
+		return result, nil
+	})
+	m.define("clamp", 3, 3, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		x, err := numberArguments("math.clamp", arguments)
+		if err != nil {
+			return nil, err
+		}
+		if x[1] > x[2] {
+			return nil, &RuntimeError{Message: "math.clamp() lower bound is greater than upper bound."}
+		}
+		return math.Max(x[1], math.Min(x[2], x[0])), nil
+	})
+	m.define("isNaN", 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		x, ok := arguments[0].(float64)
+		return ok && math.IsNaN(x), nil
+	})
+
+	return m
+}
+
+func domainError(function string, expected string, x float64) *RuntimeError {
+	return &RuntimeError{Message: fmt.Sprintf("math.%v() expects %v, got %v.", function, expected, plainString(x))}
+}
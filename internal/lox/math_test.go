@@ -0,0 +1,27 @@
+package lox
+
+import "testing"
+
+func TestMath(t *testing.T) {
+	checkScripts(t, []scriptTest{
+		{name: "functions", source: `
+print math.sqrt(16);
+print math.pow(2, 10);
+print math.floor(2.7);
+print math.ceil(2.1);
+print math.round(2.5);
+print math.abs(-3);
+print math.sin(0);`, want: "4\n1024\n2\n3\n3\n3\n0\n"},
+		{name: "min, max and clamp", source: `
+print math.min(3, 1, 2);
+print math.max(3, 1, 2);
+print math.clamp(5, 0, 3);`, want: "1\n3\n3\n"},
+		{name: "constants", source: `print math.floor(math.PI * 1000); print math.log(math.E);`, want: "3141\n1\n"},
+		{name: "not a number", source: `math.sqrt("x");`,
+			err: "math.sqrt() expects numbers."},
+		{name: "fractional power", source: `math.pow(-8, 0.5);`,
+			err: "math.pow() can't raise negative number -8 to fractional power"},
+		{name: "unknown member", source: `math.tau;`,
+			err: "Module 'math' has no member 'tau'."},
+	})
+}
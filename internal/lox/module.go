@@ -0,0 +1,38 @@
+package lox
+
+import "fmt"
+
+// LoxModule is a namespace of native values, such as math, read with '.'.
+type LoxModule struct {
+	name    string
+	members map[string]interface{}
+}
+
+func (m *LoxModule) toString() string {
+	return "<module " + m.name + ">"
+}
+
+func (m *LoxModule) get(name Token) (interface{}, *RuntimeError) {
+	if value, exists := m.members[name.Lexeme]; exists {
+		return value, nil
+	}
+	return nil, &RuntimeError{Token: name, Message: fmt.Sprintf("Module '%v' has no member '%v'.", m.name, name.Lexeme)}
+}
+
+// define adds a native function to the module, named after it in errors.
+func (m *LoxModule) define(name string, minArity, maxArity int, fn func(arguments []interface{}) (interface{}, *RuntimeError)) {
+	m.members[name] = &NativeFunction{m.name + "." + name, minArity, maxArity, fn}
+}
+
+// numberArguments checks that every argument to function is a number.
+func numberArguments(function string, arguments []interface{}) ([]float64, *RuntimeError) {
+	numbers := make([]float64, len(arguments))
+	for i, argument := range arguments {
+		number, ok := argument.(float64)
+		if !ok {
+			return nil, &RuntimeError{Message: fmt.Sprintf("%v() expects numbers.", function)}
+		}
+		numbers[i] = number
+	}
+	return numbers, nil
+}
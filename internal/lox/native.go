@@ -0,0 +1,26 @@
+package lox
+
+// variadic is the maximum arity of a callable that accepts any number of
+// arguments past its minimum.
+const variadic = -1
+
+// NativeFunction implements the Callable interface for functions written in Go.
+type NativeFunction struct {
+	name     string
+	minArity int
+	// maxArity is the most arguments accepted, or variadic for no limit.
+	maxArity int
+	fn       func(arguments []interface{}) (interface{}, *RuntimeError)
+}
+
+func (nf *NativeFunction) call(arguments []interface{}) (interface{}, *RuntimeError) {
+	return nf.fn(arguments)
+}
+
+func (nf *NativeFunction) arity() (int, int) {
+	return nf.minArity, nf.maxArity
+}
+
+func (nf *NativeFunction) toString() string {
+	return "<native fn " + nf.name + ">"
+}
@@ -3,12 +3,18 @@ package lox
 import (
 	"errors"
 	"fmt"
+	"slices"
 )
 
 type Parser struct {
 	Tokens    []Token
 	current   int
 	loopDepth int
+	// labels holds the labels of the loops enclosing the current statement.
+	labels []string
+	// closers maps the index of each '(' to the index of its ')', or -1 when
+	// it has none. It is built on first use; see isArrowFunction.
+	closers []int
 }
 
 func (p *Parser) Parse() ([]Stmt, error) {
@@ -52,19 +58,66 @@ func (p *Parser) declaration() (stmt Stmt, err ParseError) {
 		}
 	}()
 
+	if p.match(AT) {
+		return p.decoratedDeclaration(), nil
+	}
 	if p.match(CLASS) {
 		return p.classDeclaration(), nil
 	}
-	if p.match(FUN) {
+	if p.match(TRAIT) {
+		return p.traitDeclaration(), nil
+	}
+	if p.match(ENUM) {
+		return p.enumDeclaration(), nil
+	}
+	if p.match(ASYNC) {
+		p.consume(FUN, "Expect 'fun' after 'async'.")
+		function := p.function("function").(*Function)
+		function.async = true
+		return function, nil
+	}
+	// Without a name, 'fun' starts an anonymous function expression.
+	if p.check(FUN) && p.checkNext(IDENTIFIER) {
+		p.advance()
 		return p.function("function"), nil
 	}
 	if p.match(VAR) {
 		stmt = p.varDeclaration()
+	} else if p.match(CONST) {
+		stmt = p.constDeclaration()
 	} else {
 		stmt = p.statement()
 	}
 	return stmt, err
 }
+
+// decorators parses the `@expr` lines in front of a declaration, after the first '@'.
+func (p *Parser) decorators() []Expr {
+	var decorators []Expr
+	for {
+		decorators = append(decorators, p.call())
+		if !p.match(AT) {
+			return decorators
+		}
+	}
+}
+
+// decoratedDeclaration parses a function or class declaration with decorators.
+func (p *Parser) decoratedDeclaration() Stmt {
+	decorators := p.decorators()
+	if p.match(CLASS) {
+		class := p.classDeclaration().(*Class)
+		class.decorators = decorators
+		return class
+	}
+
+	async := p.match(ASYNC)
+	p.consume(FUN, "Expect function or class after decorator.")
+	function := p.function("function").(*Function)
+	function.async = async
+	function.decorators = decorators
+	return function
+}
 func (p *Parser) classDeclaration() Stmt {
 	name := p.consume(IDENTIFIER, "Expect class name.")
 	var superclass *Variable
@@ -72,21 +125,103 @@ func (p *Parser) classDeclaration() Stmt {
 		p.consume(IDENTIFIER, "Expect superclass name.")
 		superclass = &Variable{p.previous()}
 	}
+	var traits []*Variable
+	if p.match(WITH) {
+		for {
+			p.consume(IDENTIFIER, "Expect trait name.")
+			traits = append(traits, &Variable{p.previous()})
+			if !p.match(COMMA) {
+				break
+			}
+		}
+	}
 	p.consume(LEFT_BRACE, "Expect '{' before class body.")
 
+	var methods, getters, setters []*Function
+	for !p.check(RIGHT_BRACE) && !p.isAtEnd() {
+		var decorators []Expr
+		if p.match(AT) {
+			decorators = p.decorators()
+			if p.check(IDENTIFIER) && (p.checkNext(LEFT_BRACE) || p.peek().Lexeme == "set" && p.checkNext(IDENTIFIER)) {
+				loxError(p.peek(), "Can only decorate methods, not getters or setters.")
+			}
+		}
+		if p.check(IDENTIFIER) && p.checkNext(LEFT_BRACE) {
+			getters = append(getters, p.getter())
+		} else if p.check(IDENTIFIER) && p.peek().Lexeme == "set" && p.checkNext(IDENTIFIER) {
+			p.advance()
+			setters = append(setters, p.setter())
+		} else {
+			async := p.match(ASYNC)
+			newFunc := p.function("method").(*Function)
+			newFunc.async = async
+			newFunc.decorators = decorators
+			methods = append(methods, newFunc)
+		}
+	}
+
+	p.consume(RIGHT_BRACE, "Expect '}' after class body.")
+	return &Class{
+		name:       name,
+		superclass: superclass,
+		traits:     traits,
+		methods:    methods,
+		getters:    getters,
+		setters:    setters,
+	}
+}
+func (p *Parser) traitDeclaration() Stmt {
+	name := p.consume(IDENTIFIER, "Expect trait name.")
+	p.consume(LEFT_BRACE, "Expect '{' before trait body.")
+
 	var methods []*Function
 	for !p.check(RIGHT_BRACE) && !p.isAtEnd() {
 		newFunc := p.function("method")
 		methods = append(methods, newFunc.(*Function))
 	}
 
-	p.consume(RIGHT_BRACE, "Expect '}' after class body.")
-	return &Class{name: name, methods: methods, superclass: superclass}
+	p.consume(RIGHT_BRACE, "Expect '}' after trait body.")
+	return &Trait{name: name, methods: methods}
+}
+
+// enumDeclaration parses `enum Name { A, B, C; methods... }`. The ';' is only
+// needed when methods follow the members.
+func (p *Parser) enumDeclaration() Stmt {
+	name := p.consume(IDENTIFIER, "Expect enum name.")
+	p.consume(LEFT_BRACE, "Expect '{' before enum body.")
+
+	var members []Token
+	for !p.check(RIGHT_BRACE) && !p.check(SEMICOLON) {
+		members = append(members, p.consume(IDENTIFIER, "Expect enum member name."))
+		if !p.match(COMMA) {
+			break
+		}
+	}
+
+	var methods []*Function
+	if p.match(SEMICOLON) {
+		for !p.check(RIGHT_BRACE) && !p.isAtEnd() {
+			newFunc := p.function("method")
+			methods = append(methods, newFunc.(*Function))
+		}
+	}
+
+	p.consume(RIGHT_BRACE, "Expect '}' after enum body.")
+	return &Enum{name: name, members: members, methods: methods}
 }
 func (p *Parser) statement() Stmt {
+	if p.check(IDENTIFIER) && p.checkNext(COLON) {
+		return p.labeledStatement()
+	}
 	if p.match(FOR) {
 		return p.forStatement()
 	}
+	if p.match(DO) {
+		return p.doStatement()
+	}
+	if p.match(SWITCH) {
+		return p.switchStatement()
+	}
 	if p.match(IF) {
 		return p.ifStatement()
 	}
@@ -107,6 +242,82 @@ func (p *Parser) statement() Stmt {
 	}
 	return p.expressionStatement()
 }
+func (p *Parser) labeledStatement() Stmt {
+	label := p.advance()
+	p.advance() // The ':'.
+
+	if !p.check(WHILE) && !p.check(FOR) && !p.check(DO) {
+		panic(loxError(label, "Only loops can be labeled."))
+	}
+	if slices.Contains(p.labels, label.Lexeme) {
+		loxError(label, fmt.Sprintf("Label '%v' is already in use.", label.Lexeme))
+	}
+
+	p.labels = append(p.labels, label.Lexeme)
+	defer func() { p.labels = p.labels[:len(p.labels)-1] }()
+
+	return &Labeled{label, p.statement()}
+}
+func (p *Parser) doStatement() Stmt {
+	p.loopDepth++
+	body := p.statement()
+	p.loopDepth--
+
+	p.consume(WHILE, "Expect 'while' after do-while body.")
+	p.consume(LEFT_PAREN, "Expect '(' after 'while'.")
+	condition := p.expression()
+	p.consume(RIGHT_PAREN, "Expect ')' after condition.")
+	p.consume(SEMICOLON, "Expect ';' after do-while condition.")
+
+	return &DoWhile{body, condition}
+}
+
+// switchStatement parses a switch. Cases don't fall through, so 'break' inside
+// one still refers to the enclosing loop.
+func (p *Parser) switchStatement() Stmt {
+	keyword := p.previous()
+	p.consume(LEFT_PAREN, "Expect '(' after 'switch'.")
+	value := p.expression()
+	p.consume(RIGHT_PAREN, "Expect ')' after switch value.")
+	p.consume(LEFT_BRACE, "Expect '{' before switch body.")
+
+	var cases []SwitchCase
+	var defaultBranch *Block
+	for !p.check(RIGHT_BRACE) && !p.isAtEnd() {
+		if p.match(CASE) {
+			var values []Expr
+			for {
+				values = append(values, p.expression())
+				if !p.match(COMMA) {
+					break
+				}
+			}
+			p.consume(COLON, "Expect ':' after case value.")
+			cases = append(cases, SwitchCase{values, p.caseBody()})
+		} else if p.match(DEFAULT) {
+			if defaultBranch != nil {
+				loxError(p.previous(), "A switch can only have one default case.")
+			}
+			p.consume(COLON, "Expect ':' after 'default'.")
+			defaultBranch = p.caseBody()
+		} else {
+			panic(loxError(p.peek(), "Expect 'case' or 'default'."))
+		}
+	}
+
+	p.consume(RIGHT_BRACE, "Expect '}' after switch body.")
+	return &Switch{keyword, value, cases, defaultBranch}
+}
+
+// caseBody parses the statements of a switch case up to the next case.
+func (p *Parser) caseBody() *Block {
+	var statements []Stmt
+	for !p.check(CASE) && !p.check(DEFAULT) && !p.check(RIGHT_BRACE) && !p.isAtEnd() {
+		dec, _ := p.declaration()
+		statements = append(statements, dec)
+	}
+	return &Block{statements}
+}
 func (p *Parser) forStatement() Stmt {
 	p.loopDepth++
 	defer func() { p.loopDepth-- }()
@@ -193,10 +404,16 @@ func (p *Parser) varDeclaration() Stmt {
 	return &Var{name: name, initializer: initializer}
 }
 
-func (p *Parser) whileStatement() Stmt {
-	p.loopDepth++
-	defer func() {}()
+func (p *Parser) constDeclaration() Stmt {
+	name := p.consume(IDENTIFIER, "Expect constant name.")
+	p.consume(EQUAL, "Expect '=' after constant name.")
+	initializer := p.expression()
 
+	p.consume(SEMICOLON, "Expect ';' after constant declaration.")
+	return &Var{name: name, initializer: initializer, constant: true}
+}
+
+func (p *Parser) whileStatement() Stmt {
 	p.consume(LEFT_PAREN, "Expect '(' after 'while'.")
 	condition := p.expression()
 	p.consume(RIGHT_PAREN, "Expect ')' after condition.")
@@ -209,12 +426,19 @@ func (p *Parser) whileStatement() Stmt {
 }
 
 func (p *Parser) breakStatement() Stmt {
+	keyword := p.previous()
 	if p.loopDepth == 0 {
-		loxError(p.previous(), "Cannot use 'break' outside of a loop.")
+		loxError(keyword, "Cannot use 'break' outside of a loop.")
+	}
+
+	var label *Token
+	if p.match(IDENTIFIER) {
+		name := p.previous()
+		label = &name
 	}
 
 	p.consume(SEMICOLON, "Expect ';' after 'break'.")
-	return &Break{}
+	return &Break{keyword, label}
 }
 
 func (p *Parser) expressionStatement() Stmt {
@@ -226,38 +450,143 @@ func (p *Parser) function(kind string) Stmt {
 	name := p.consume(IDENTIFIER, fmt.Sprintf("Expect %v name.", kind))
 
 	p.consume(LEFT_PAREN, fmt.Sprintf("Expect '(' after %v name.", kind))
-	var parameters []Token
-	if !p.check(RIGHT_PAREN) {
-		for {
-			parameters = append(parameters, p.consume(IDENTIFIER, "Expect parameter name."))
-			if !p.match(COMMA) {
-				break
-			}
-		}
-	}
+	parameters, defaults, rest := p.parameters()
 	p.consume(RIGHT_PAREN, "Expect ')' after parameters.")
 	p.consume(LEFT_BRACE, fmt.Sprintf("Expect '{' before %v body.", kind))
-	body := p.block()
+	body := p.functionBody()
 
-	return &Function{&name, parameters, body}
+	return &Function{&name, parameters, defaults, rest, body, false, nil}
 }
-func (p *Parser) anonFunction() Expr {
-	p.consume(LEFT_PAREN, "Expect '(' before anonymous function parameters.")
+
+// parameters parses a parameter list up to the closing ')'. Each parameter may
+// have a default value, which is nil in the returned defaults when absent. A
+// final `...name` parameter collects any extra arguments and is returned as rest.
+func (p *Parser) parameters() ([]Token, []Expr, *Token) {
 	var parameters []Token
-	if !p.check(RIGHT_PAREN) {
-		for {
-			parameters = append(parameters, p.consume(IDENTIFIER, "Expect parameter name."))
-			if !p.match(COMMA) {
-				break
+	var defaults []Expr
+	if p.check(RIGHT_PAREN) {
+		return parameters, defaults, nil
+	}
+	for {
+		if len(parameters) >= 255 {
+			loxError(p.peek(), "Can't have more than 255 parameters.")
+		}
+		if p.match(DOT_DOT_DOT) {
+			rest := p.consume(IDENTIFIER, "Expect rest parameter name.")
+			if p.check(COMMA) {
+				panic(loxError(p.peek(), "A rest parameter must be the last parameter."))
 			}
+			return parameters, defaults, &rest
+		}
+		param := p.consume(IDENTIFIER, "Expect parameter name.")
+
+		var value Expr
+		if p.match(EQUAL) {
+			value = p.expression()
+		} else if len(defaults) > 0 && defaults[len(defaults)-1] != nil {
+			loxError(param, "A parameter without a default can't follow one with a default.")
+		}
+		parameters = append(parameters, param)
+		defaults = append(defaults, value)
+
+		if !p.match(COMMA) {
+			break
 		}
 	}
+	return parameters, defaults, nil
+}
+
+// getter parses a method declared without a parameter list, which runs when the property is read.
+func (p *Parser) getter() *Function {
+	name := p.consume(IDENTIFIER, "Expect getter name.")
+	p.consume(LEFT_BRACE, "Expect '{' before getter body.")
+	body := p.functionBody()
+
+	return &Function{&name, nil, nil, nil, body, false, nil}
+}
+
+// setter parses a `set name(value) { ... }` declaration, which runs when the property is assigned.
+func (p *Parser) setter() *Function {
+	name := p.consume(IDENTIFIER, "Expect setter name.")
+	p.consume(LEFT_PAREN, "Expect '(' after setter name.")
+	param := p.consume(IDENTIFIER, "Expect setter parameter name.")
+	if p.check(COMMA) {
+		loxError(p.peek(), "A setter must take exactly one parameter.")
+	}
+	p.consume(RIGHT_PAREN, "Expect ')' after setter parameter.")
+	p.consume(LEFT_BRACE, "Expect '{' before setter body.")
+	body := p.functionBody()
+
+	return &Function{&name, []Token{param}, []Expr{nil}, nil, body, false, nil}
+}
+
+// anonFunction parses `fun (params) { body }`. Anonymous functions have no name,
+// so the 'fun' keyword stands in for it.
+func (p *Parser) anonFunction(async bool) Expr {
+	keyword := p.previous()
+	p.consume(LEFT_PAREN, "Expect '(' before anonymous function parameters.")
+	parameters, defaults, rest := p.parameters()
 	p.consume(RIGHT_PAREN, "Expect ')' after parameters.")
 	p.consume(LEFT_BRACE, "Expect '{' before anonymous function body.")
-	body := p.block()
+	body := p.functionBody()
 
 	// Return the anonymous function as an expression
-	return &AnonFunction{params: parameters, body: body}
+	return &AnonFunction{&Function{&keyword, parameters, defaults, rest, body, async, nil}}
+}
+
+// arrowFunction parses `(params) => expression` or `(params) => { body }`. The
+// opening '(' stands in for the missing name.
+func (p *Parser) arrowFunction() Expr {
+	paren := p.consume(LEFT_PAREN, "Expect '(' before arrow function parameters.")
+	parameters, defaults, rest := p.parameters()
+	p.consume(RIGHT_PAREN, "Expect ')' after parameters.")
+	arrow := p.consume(ARROW, "Expect '=>' after arrow function parameters.")
+
+	var body []Stmt
+	if p.match(LEFT_BRACE) {
+		body = p.functionBody()
+	} else {
+		body = []Stmt{&Return{arrow, p.expression()}}
+	}
+
+	return &AnonFunction{&Function{&paren, parameters, defaults, rest, body, false, nil}}
+}
+
+// isArrowFunction reports whether the '(' at the current token closes with a
+// ')' followed by '=>'. The matching parens are found in one pass over the
+// tokens, so nested groupings don't each scan ahead to their end.
+func (p *Parser) isArrowFunction() bool {
+	if p.closers == nil {
+		p.closers = make([]int, len(p.Tokens))
+		var open []int
+		for i, token := range p.Tokens {
+			p.closers[i] = -1
+			switch token.Type {
+			case LEFT_PAREN:
+				open = append(open, i)
+			case RIGHT_PAREN:
+				if len(open) > 0 {
+					p.closers[open[len(open)-1]] = i
+					open = open[:len(open)-1]
+				}
+			}
+		}
+	}
+
+	closer := p.closers[p.current]
+	return closer >= 0 && closer+1 < len(p.Tokens) && p.Tokens[closer+1].Type == ARROW
+}
+
+// functionBody parses a function's block. Loops around the declaration don't
+// extend into the body, so 'break' can't reach them.
+func (p *Parser) functionBody() []Stmt {
+	loopDepth, labels := p.loopDepth, p.labels
+	p.loopDepth, p.labels = 0, nil
+	defer func() {
+		p.loopDepth, p.labels = loopDepth, labels
+	}()
+
+	return p.block()
 }
 func (p *Parser) block() []Stmt {
 	var statements []Stmt
@@ -271,7 +600,7 @@ func (p *Parser) block() []Stmt {
 }
 
 func (p *Parser) assignment() Expr {
-	expr := p.or()
+	expr := p.pipe()
 
 	if p.match(EQUAL) {
 		equals := p.previous()
@@ -287,6 +616,26 @@ func (p *Parser) assignment() Expr {
 	}
 	return expr
 }
+func (p *Parser) pipe() Expr {
+	expr := p.coalesce()
+
+	for p.match(PIPE_GREATER) {
+		operator := p.previous()
+		right := p.coalesce()
+		expr = &Pipe{expr, operator, right}
+	}
+	return expr
+}
+func (p *Parser) coalesce() Expr {
+	expr := p.or()
+
+	for p.match(QUESTION_QUESTION) {
+		operator := p.previous()
+		right := p.or()
+		expr = &Logical{expr, operator, right}
+	}
+	return expr
+}
 func (p *Parser) or() Expr {
 	expr := p.and()
 
@@ -322,7 +671,7 @@ func (p *Parser) equality() Expr {
 func (p *Parser) comparison() Expr {
 	expr := p.term()
 
-	for p.match(GREATER, GREATER_EQUAL, LESS, LESS_EQUAL) {
+	for p.match(GREATER, GREATER_EQUAL, LESS, LESS_EQUAL, IS) {
 		operator := p.previous()
 		right := p.term()
 		expr = &Binary{expr, operator, right}
@@ -355,6 +704,19 @@ func (p *Parser) factor() Expr {
 }
 
 func (p *Parser) unary() Expr {
+	if p.match(AWAIT) {
+		keyword := p.previous()
+		value := p.unary()
+		return &Await{keyword, value}
+	}
+	if p.match(SPAWN) {
+		keyword := p.previous()
+		call, ok := p.call().(*Call)
+		if !ok {
+			panic(loxError(keyword, "Expect a function call after 'spawn'."))
+		}
+		return &Spawn{keyword, call}
+	}
 	if p.match(BANG, MINUS) {
 		operator := p.previous()
 		right := p.unary()
@@ -364,35 +726,86 @@ func (p *Parser) unary() Expr {
 }
 func (p *Parser) finishCall(callee Expr) Expr {
 	var arguments []Expr
+	var names []*Token
 	if !p.check(RIGHT_PAREN) {
-		arguments = append(arguments, p.expression())
-		for p.match(COMMA) {
-			arguments = append(arguments, p.expression())
+		for {
+			name := p.argumentName()
+			if name == nil && len(names) > 0 && names[len(names)-1] != nil {
+				loxError(p.peek(), "Positional argument can't follow a named argument.")
+			}
+			if name == nil && p.match(DOT_DOT_DOT) {
+				// The argument limit counts a spread as one argument; the
+				// list it expands to is only known at runtime.
+				arguments = append(arguments, &Spread{p.previous(), p.expression()})
+			} else {
+				arguments = append(arguments, p.expression())
+			}
+			names = append(names, name)
 			if len(arguments) >= 255 {
 				loxError(p.peek(), "Can't have more than 255 arguments.")
 			}
+			if !p.match(COMMA) {
+				break
+			}
 		}
 	}
 	paren := p.consume(RIGHT_PAREN, "Expect ')' after arguments.")
 
-	return &Call{callee, paren, arguments}
+	return &Call{callee, paren, arguments, names}
+}
+
+// argumentName consumes the `name:` prefix of a named argument, if there is one.
+func (p *Parser) argumentName() *Token {
+	if !p.check(IDENTIFIER) || !p.checkNext(COLON) {
+		return nil
+	}
+	name := p.advance()
+	p.advance()
+	return &name
 }
 func (p *Parser) call() Expr {
 	expr := p.primary()
 
+	optional := false
 	for {
 		if p.match(LEFT_PAREN) {
 			expr = p.finishCall(expr)
 		} else if p.match(DOT) {
 			name := p.consume(IDENTIFIER, "Expect property name after '.'.")
-			expr = &Get{expr, name}
+			expr = &Get{expr, name, false}
+		} else if p.match(QUESTION_DOT) {
+			name := p.consume(IDENTIFIER, "Expect property name after '?.'.")
+			expr = &Get{expr, name, true}
+			optional = true
+		} else if p.match(LEFT_BRACKET) {
+			expr = p.finishIndex(expr)
 		} else {
 			break
 		}
 	}
 
+	// A nil link anywhere in an optional chain makes the whole chain nil.
+	if optional {
+		return &Optional{expr}
+	}
 	return expr
 }
+
+// finishIndex parses `[i]` or a slice `[start:end]`, where either bound of a
+// slice may be left out.
+func (p *Parser) finishIndex(object Expr) Expr {
+	bracket := p.previous()
+	var start, end Expr
+	if !p.check(COLON) {
+		start = p.expression()
+	}
+	slice := p.match(COLON)
+	if slice && !p.check(RIGHT_BRACKET) {
+		end = p.expression()
+	}
+	p.consume(RIGHT_BRACKET, "Expect ']' after index.")
+	return &Index{object, bracket, start, end, slice}
+}
 func (p *Parser) primary() Expr {
 	if p.match(FALSE) {
 		return &Literal{false}
@@ -408,7 +821,14 @@ func (p *Parser) primary() Expr {
 	}
 	if p.match(FUN) {
 		// Directly parse the anonymous function as an expression
-		return p.anonFunction()
+		return p.anonFunction(false)
+	}
+	if p.match(ASYNC) {
+		p.consume(FUN, "Expect 'fun' after 'async'.")
+		return p.anonFunction(true)
+	}
+	if p.check(LEFT_PAREN) && p.isArrowFunction() {
+		return p.arrowFunction()
 	}
 	if p.match(LEFT_PAREN) {
 		expr := p.expression()
@@ -458,6 +878,13 @@ func (p *Parser) check(tokenType TokenType) bool {
 	return p.peek().Type == tokenType
 }
 
+func (p *Parser) checkNext(tokenType TokenType) bool {
+	if p.isAtEnd() || p.Tokens[p.current+1].Type == EOF {
+		return false
+	}
+	return p.Tokens[p.current+1].Type == tokenType
+}
+
 func (p *Parser) advance() Token {
 	if !p.isAtEnd() {
 		p.current++
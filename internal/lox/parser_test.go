@@ -0,0 +1,74 @@
+package lox
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnonymousFunctions(t *testing.T) {
+	deep := strings.Repeat("(", 2000) + "1" + strings.Repeat(")", 2000)
+	checkScripts(t, []scriptTest{
+		{name: "closure", source: `
+fun counter() { var n = 0; return fun () { n = n + 1; return n; }; }
+var c = counter();
+c();
+print c();`, want: "2\n"},
+		{name: "arrow", source: `
+var add = (a, b) => a + b;
+var square = (x) => { return x * x; };
+var seven = () => 7;
+var twice = (a, b = 2) => a * b;
+print add(2, 3);
+print square(4);
+print seven();
+print twice(5);`, want: "5\n16\n7\n10\n"},
+		{name: "groupings", source: `var a; print (1 + 2) * 3; print (a = 4);`, want: "9\n4\n"},
+		{name: "deep groupings", source: "print " + deep + ";", want: "1\n"},
+	})
+}
+
+func TestControlFlow(t *testing.T) {
+	checkScripts(t, []scriptTest{
+		{name: "switch", source: `
+fun f(x) {
+  switch (x) {
+    case 1: print "one";
+    case 2, 3: print "two or three";
+    default: print "other";
+  }
+}
+f(1); f(3); f(9);`, want: "one\ntwo or three\nother\n"},
+		{name: "break in switch", source: `
+var i = 0;
+while (true) { switch (i) { case 3: break; default: i = i + 1; } }
+print i;`, want: "3\n"},
+		{name: "do-while", source: `
+var i = 0;
+do { i = i + 1; } while (i < 3);
+print i;
+do print "once"; while (false);`, want: "3\nonce\n"},
+		{name: "labeled break", source: `
+outer: for (var i = 0; i < 3; i = i + 1) {
+  for (var j = 0; j < 3; j = j + 1) {
+    if (j == 1) break outer;
+    print i + j;
+  }
+}`, want: "0\n"},
+		{name: "exhaustive enum switch", source: `
+enum Color { Red, Green }
+fun f(c) { switch (c) { case Color.Red: print "r"; case Color.Green: print "g"; } }
+f(Color.Green);`, want: "g\n"},
+		{name: "missing enum case", source: `
+enum C { R, G }
+switch (C.R) { case C.R: print 1; }`, err: "Switch on enum 'C' is missing case G."},
+		{name: "label in use", source: `a: while (true) { a: while (true) {} }`,
+			err: "Label 'a' is already in use."},
+		{name: "label on non-loop", source: `a: print 1;`,
+			err: "Only loops can be labeled."},
+	})
+
+	_, stderr, _ := runScript(t, `while (true) { break foo; }`)
+	if strings.Count(stderr, "Undefined label 'foo'.") != 1 {
+		t.Errorf("got error output %q, want the undefined label reported once", stderr)
+	}
+}
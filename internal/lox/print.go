@@ -0,0 +1,173 @@
+package lox
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type Stringer interface {
+	toString() string
+}
+
+// stringify converts a value to the text print and string concatenation
+// show. An instance whose class defines toString() is converted by calling it,
+// including inside lists.
+func stringify(value interface{}) (string, *RuntimeError) {
+	return stringifyValue(value, make(map[interface{}]bool), true)
+}
+
+// plainString formats a value without running any Lox code.
+func plainString(value interface{}) string {
+	text, _ := stringifyValue(value, make(map[interface{}]bool), false)
+	return text
+}
+
+// stringifyValue backs stringify and plainString, calling toString() methods
+// only when callToString is set. seen holds the lists and maps being
+// formatted, so cycles print as "...".
+func stringifyValue(value interface{}, seen map[interface{}]bool, callToString bool) (string, *RuntimeError) {
+	switch v := value.(type) {
+	case nil:
+		return "nil", nil
+	case float64:
+		return formatNumber(v), nil
+	case *LoxInstance:
+		method := v.class.findMethod("toString")
+		if method == nil || !callToString {
+			break
+		}
+		bound, err := v.bindMethod(method)
+		if err != nil {
+			return "", err
+		}
+		result, err := callValue(bound, *method.declaration.name, nil, nil)
+		if err != nil {
+			return "", err
+		}
+		text, ok := result.(string)
+		if !ok {
+			return "", &RuntimeError{Token: *method.declaration.name, Message: "toString() must return a string."}
+		}
+		return text, nil
+	case *LoxList:
+		if seen[v] {
+			return "[...]", nil
+		}
+		seen[v] = true
+		defer delete(seen, v)
+
+		parts := make([]string, len(v.elements))
+		for i, element := range v.elements {
+			text, err := stringifyValue(element, seen, callToString)
+			if err != nil {
+				return "", err
+			}
+			parts[i] = text
+		}
+		return "[" + strings.Join(parts, ", ") + "]", nil
+	case *LoxMap:
+		if seen[v] {
+			return "{...}", nil
+		}
+		seen[v] = true
+		defer delete(seen, v)
+
+		parts := make([]string, len(v.keys))
+		for i, key := range v.keys {
+			keyText, err := stringifyValue(key, seen, callToString)
+			if err != nil {
+				return "", err
+			}
+			valueText, err := stringifyValue(v.values[key], seen, callToString)
+			if err != nil {
+				return "", err
+			}
+			parts[i] = keyText + ": " + valueText
+		}
+		return "{" + strings.Join(parts, ", ") + "}", nil
+	}
+
+	if stringer, ok := value.(Stringer); ok {
+		return stringer.toString(), nil
+	}
+	// Fallback for other types, using fmt.Sprintf to handle them
+	return fmt.Sprintf("%v", value), nil
+}
+
+// hasToString reports whether value is an instance whose class defines toString().
+func hasToString(value interface{}) bool {
+	instance, ok := value.(*LoxInstance)
+	return ok && instance.class.findMethod("toString") != nil
+}
+
+// formatNumber writes a number in the shortest form that reads back as the
+// same number. Very large and very small numbers use exponent notation.
+func formatNumber(n float64) string {
+	switch {
+	case math.IsNaN(n):
+		return "nan"
+	case math.IsInf(n, 1):
+		return "inf"
+	case math.IsInf(n, -1):
+		return "-inf"
+	}
+	if abs := math.Abs(n); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		return strconv.FormatFloat(n, 'g', -1, 64)
+	}
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// repr formats a value for debugging: strings are quoted and instances list
+// their fields. It never calls toString() methods. seen holds the lists and
+// instances being formatted, so cycles print as "...".
+func repr(value interface{}, seen map[interface{}]bool) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case *LoxList:
+		if seen[v] {
+			return "[...]"
+		}
+		seen[v] = true
+		defer delete(seen, v)
+
+		parts := make([]string, len(v.elements))
+		for i, element := range v.elements {
+			parts[i] = repr(element, seen)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case *LoxMap:
+		if seen[v] {
+			return "{...}"
+		}
+		seen[v] = true
+		defer delete(seen, v)
+
+		parts := make([]string, len(v.keys))
+		for i, key := range v.keys {
+			parts[i] = repr(key, seen) + ": " + repr(v.values[key], seen)
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	case *LoxInstance:
+		if seen[v] || len(v.fields) == 0 {
+			return v.toString()
+		}
+		seen[v] = true
+		defer delete(seen, v)
+
+		var names []string
+		for name := range v.fields {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		parts := make([]string, len(names))
+		for i, name := range names {
+			parts[i] = name + ": " + repr(v.fields[name], seen)
+		}
+		return fmt.Sprintf("<%v instance {%v}>", v.class.name, strings.Join(parts, ", "))
+	}
+	return plainString(value)
+}
@@ -0,0 +1,45 @@
+package lox
+
+import "testing"
+
+func TestPrinting(t *testing.T) {
+	checkScripts(t, []scriptTest{
+		{name: "formats", source: `
+class A { m() {} }
+print clock;
+print A;
+print A();
+print A().m;
+print nil;`, want: "<native fn clock>\n<class A>\n<A instance>\n<fn m>\nnil\n"},
+		{name: "toString", source: `
+class P { init(n) { this.n = n; } toString() { return "P" + this.n; } }
+print P("1");
+print List(P("2"));
+print "a " + P("3");
+print P("4") + "!";`, want: "P1\n[P2]\na P3\nP4!\n"},
+		{name: "repr", source: `
+class P { init() { this.s = "x"; } toString() { return "P"; } }
+print repr(P());
+print repr(List("a", 1));`, want: "<P instance {s: \"x\"}>\n[\"a\", 1]\n"},
+		{name: "cycles", source: `
+var l = List(1);
+l.push(l);
+var m = Map();
+m.set("self", m);
+print l;
+print m;
+print repr(l);`, want: "[1, [...]]\n{self: {...}}\n[1, [...]]\n"},
+		{name: "not a string", source: `class P { toString() { return 1; } } print P();`,
+			err: "toString() must return a string."},
+		{name: "error in toString", source: `
+class P {
+  toString() { return nil.x; }
+}
+async fun show(p) { print p; }
+show(P());`, err: "Only instances have properties.\n[line 3] in <fn toString>\n"},
+		{name: "string plus number", source: `print "a" + 1;`,
+			err: "operands must be two numbers or two strings"},
+		{name: "string plus instance", source: `class Q {} print "a" + Q();`,
+			err: "operands must be two numbers or two strings"},
+	})
+}
@@ -0,0 +1,72 @@
+package lox
+
+import (
+	"fmt"
+	"os"
+)
+
+// SetArgs sets the script arguments, which scripts see as the list args.
+func (i *Interpreter) SetArgs(args []string) {
+	i.globals.define("args", stringList(args))
+}
+
+// exit stops the program with status. The error it returns unwinds the Go
+// stack like any other, so the interpreter's own cleanup still runs, and
+// nothing is reported for it.
+func (i *Interpreter) exit(status int) *RuntimeError {
+	i.exited = true
+	i.exitStatus = status
+	// Wake blocked tasks so they can unwind too.
+	i.notify()
+	return exitError()
+}
+
+// exitError is the error that unwinds a task once exit() has been called.
+func exitError() *RuntimeError {
+	return &RuntimeError{Message: "exit"}
+}
+
+// defineProcessNatives registers args, env and exit.
+func defineProcessNatives(i *Interpreter) {
+	i.SetArgs(nil)
+
+	env := &LoxModule{name: "env", members: make(map[string]interface{})}
+	// get returns the value of an environment variable, or nil if it isn't set.
+	env.define("get", 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		name, err := stringArgument("env.get", arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		if value, exists := os.LookupEnv(name); exists {
+			return value, nil
+		}
+		return nil, nil
+	})
+	env.define("set", 2, 2, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		name, err := stringArgument("env.set", arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		value, err := stringArgument("env.set", arguments[1])
+		if err != nil {
+			return nil, err
+		}
+		if err := os.Setenv(name, value); err != nil {
+			return nil, &RuntimeError{Message: fmt.Sprintf("env.set(): %v.", err)}
+		}
+		return nil, nil
+	})
+	i.globals.define("env", env)
+
+	i.globals.define("exit", &NativeFunction{"exit", 0, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		status := 0.0
+		if len(arguments) == 1 {
+			code, ok := arguments[0].(float64)
+			if !ok || code != float64(int(code)) || code < 0 || code > 255 {
+				return nil, &RuntimeError{Message: "exit() expects a status from 0 to 255."}
+			}
+			status = code
+		}
+		return nil, i.exit(int(status))
+	}})
+}
@@ -0,0 +1,74 @@
+package lox
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestArgsAndEnv(t *testing.T) {
+	os.Unsetenv("LOX_TEST_UNSET")
+	t.Setenv("LOX_TEST_VAR", "from go")
+
+	stdout, stderr, _ := runScript(t, `
+print args;
+print args.length;
+print env.get("LOX_TEST_VAR");
+print env.get("LOX_TEST_UNSET");
+env.set("LOX_TEST_VAR", "from lox");
+print env.get("LOX_TEST_VAR");`, argsEnv+"=one two")
+	want := "[one, two]\n2\nfrom go\nnil\nfrom lox\n"
+	if stdout != want || stderr != "" {
+		t.Errorf("got %q, stderr %q; want %q", stdout, stderr, want)
+	}
+}
+
+func TestExitStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+		err    string
+		status int
+	}{
+		{"exit", `print "before"; exit(3); print "after";`, "before\n", "", 3},
+		{"nested", `
+fun f() { while (true) { exit(2); } }
+f();
+print "after";`, "", "", 2},
+		{"default status", `print "a"; exit();`, "a\n", "", 0},
+		{"runtime error", `print nil.field;`, "", "Only instances have properties.", 70},
+		{"compile error", `print;`, "", "Expect expression.", 65},
+		{"bad status", `exit(256);`, "", "exit() expects a status from 0 to 255.", 70},
+		{"from timer", `
+setTimeout(() => { exit(5); }, 5);
+var i = 0;
+while (i < 3) { await sleep(20); i = i + 1; print i; }`, "", "", 5},
+		{"from task", `
+fun f() { time.sleep(5); exit(4); }
+spawn f();
+var i = 0;
+while (i < 3) { await sleep(20); i = i + 1; print i; }`, "", "", 4},
+		{"from task while sleeping", `
+fun f() { exit(4); }
+spawn f();
+var i = 0;
+while (i < 3) { time.sleep(20); i = i + 1; print i; }`, "", "", 4},
+		{"from timer while blocked", `
+setTimeout(() => { exit(6); }, 5);
+var ch = Channel();
+print ch.receive();`, "", "", 6},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stdout, stderr, status := runScript(t, test.source)
+			if stdout != test.want || status != test.status {
+				t.Errorf("got %q and status %d, want %q and status %d", stdout, status, test.want, test.status)
+			}
+			if test.err == "" && stderr != "" || !strings.Contains(stderr, test.err) {
+				t.Errorf("got error output %q, want %q", stderr, test.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package lox
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Seed resets the interpreter's random number generator, so the random module
+// produces the same sequence on every run.
+func (i *Interpreter) Seed(seed int64) {
+	i.random = rand.New(rand.NewSource(seed))
+}
+
+func newRandomModule(i *Interpreter) *LoxModule {
+	m := &LoxModule{name: "random", members: make(map[string]interface{})}
+
+	// float returns a number from 0 up to, but not including, 1.
+	m.define("float", 0, 0, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		return i.random.Float64(), nil
+	})
+	// int returns an integer from lo to hi, including both.
+	m.define("int", 2, 2, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		bounds, err := numberArguments("random.int", arguments)
+		if err != nil {
+			return nil, err
+		}
+		lo, hi := bounds[0], bounds[1]
+		if lo != float64(int64(lo)) || hi != float64(int64(hi)) {
+			return nil, &RuntimeError{Message: "random.int() expects integer bounds."}
+		}
+		if lo > hi {
+			return nil, &RuntimeError{Message: "random.int() lower bound is greater than upper bound."}
+		}
+		// The size of the range has to fit in an int64 for Int63n.
+		low, high := int64(lo), int64(hi)
+		span := high - low
+		if span < 0 || span == math.MaxInt64 {
+			return nil, &RuntimeError{Message: "random.int() range is too wide."}
+		}
+		return float64(low + i.random.Int63n(span+1)), nil
+	})
+	m.define("choice", 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		list, ok := arguments[0].(*LoxList)
+		if !ok {
+			return nil, &RuntimeError{Message: "random.choice() expects a list."}
+		}
+		if len(list.elements) == 0 {
+			return nil, &RuntimeError{Message: "random.choice() can't choose from an empty list."}
+		}
+		return list.elements[i.random.Intn(len(list.elements))], nil
+	})
+	// shuffle reorders a list in place.
+	m.define("shuffle", 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		list, ok := arguments[0].(*LoxList)
+		if !ok {
+			return nil, &RuntimeError{Message: "random.shuffle() expects a list."}
+		}
+		i.random.Shuffle(len(list.elements), func(a, b int) {
+			list.elements[a], list.elements[b] = list.elements[b], list.elements[a]
+		})
+		return nil, nil
+	})
+	m.define("seed", 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		seed, ok := arguments[0].(float64)
+		if !ok || seed != float64(int64(seed)) {
+			return nil, &RuntimeError{Message: "random.seed() expects an integer."}
+		}
+		i.Seed(int64(seed))
+		return nil, nil
+	})
+
+	return m
+}
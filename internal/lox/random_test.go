@@ -0,0 +1,63 @@
+package lox
+
+import "testing"
+
+const randomScript = `
+var l = List(1, 2, 3, 4, 5);
+random.shuffle(l);
+print random.int(1, 100);
+print random.float();
+print random.choice(l);
+print l;
+`
+
+func TestSeed(t *testing.T) {
+	run := func(seed string) string {
+		stdout, stderr, _ := runScript(t, randomScript, seedEnv+"="+seed)
+		if stderr != "" {
+			t.Fatalf("got stderr %q", stderr)
+		}
+		return stdout
+	}
+
+	first, second, other := run("42"), run("42"), run("43")
+	if first != second {
+		t.Errorf("same seed gave %q and %q", first, second)
+	}
+	if first == other {
+		t.Errorf("seeds 42 and 43 both gave %q", first)
+	}
+}
+
+func TestRandomSeed(t *testing.T) {
+	stdout, stderr, _ := runScript(t, `
+random.seed(7);
+var a = List(random.int(1, 1000000), random.float());
+random.seed(7);
+var b = List(random.int(1, 1000000), random.float());
+print a[0] == b[0] and a[1] == b[1];`)
+	if stdout != "true\n" || stderr != "" {
+		t.Errorf("got %q, stderr %q; want the same numbers after reseeding", stdout, stderr)
+	}
+}
+
+func TestRandom(t *testing.T) {
+	checkScripts(t, []scriptTest{
+		{name: "int bounds", source: `
+var ok = true;
+for (var n = 0; n < 100; n = n + 1) {
+  var r = random.int(-2, 2);
+  if (r < -2 or r > 2 or r != math.floor(r)) ok = false;
+}
+print ok;
+print random.int(5, 5);`, want: "true\n5\n"},
+		{name: "wide range", source: `random.int(-9000000000000000000, 9000000000000000000);`,
+			err: "random.int() range is too wide."},
+		{name: "reversed bounds", source: `random.int(2, 1);`,
+			err: "random.int() lower bound is greater than upper bound."},
+		{name: "fractional bounds", source: `random.int(1.5, 2);`,
+			err: "random.int() expects integer bounds."},
+		{name: "empty choice", source: `random.choice(List());`,
+			err: "random.choice() can't choose from an empty list."},
+	})
+}
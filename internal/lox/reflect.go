@@ -0,0 +1,190 @@
+package lox
+
+import (
+	"fmt"
+	"slices"
+)
+
+// typeName is the name type() gives a value's type.
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "nil"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case *LoxClass:
+		return "class"
+	case *LoxInstance, *LoxEnumValue:
+		return "instance"
+	case *LoxList:
+		return "list"
+	case *LoxMap:
+		return "map"
+	case *LoxEnum:
+		return "enum"
+	case *LoxTrait:
+		return "trait"
+	case *LoxPromise:
+		return "promise"
+	case *LoxChannel:
+		return "channel"
+	case *LoxTask:
+		return "task"
+	case *LoxModule:
+		return "module"
+	case *LoxRegex:
+		return "regex"
+	case *LoxFileInfo, *LoxLineReader:
+		return "file"
+	case *LoxDate:
+		return "date"
+	case Callable:
+		return "function"
+	}
+	return "unknown"
+}
+
+// isInstance reports whether value is an instance of class, or of one of its
+// subclasses. class may also be an enum, which its members are instances of.
+func isInstance(value interface{}, class interface{}, operator Token) (bool, *RuntimeError) {
+	switch class := class.(type) {
+	case *LoxClass:
+		instance, ok := value.(*LoxInstance)
+		if !ok {
+			return false, nil
+		}
+		for c := instance.class; c != nil; c = c.superclass {
+			if c == class {
+				return true, nil
+			}
+		}
+		return false, nil
+	case *LoxEnum:
+		member, ok := value.(*LoxEnumValue)
+		return ok && member.enum == class, nil
+	}
+	return false, &RuntimeError{Token: operator, Message: "Right operand of 'is' must be a class or enum."}
+}
+
+// defineReflectionNatives registers the builtins that inspect values.
+func defineReflectionNatives(i *Interpreter) {
+	i.globals.define("type", &NativeFunction{"type", 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		return typeName(arguments[0]), nil
+	}})
+
+	i.globals.define("classOf", &NativeFunction{"classOf", 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		switch value := arguments[0].(type) {
+		case *LoxInstance:
+			return value.class, nil
+		case *LoxEnumValue:
+			return value.enum, nil
+		}
+		return nil, nil
+	}})
+
+	i.globals.define("fields", &NativeFunction{"fields", 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		instance, err := instanceArgument("fields", arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		var names []string
+		for name := range instance.fields {
+			names = append(names, name)
+		}
+		// Field order isn't kept, so list them alphabetically.
+		slices.Sort(names)
+		return stringList(names), nil
+	}})
+
+	i.globals.define("methods", &NativeFunction{"methods", 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		class, ok := arguments[0].(*LoxClass)
+		if !ok {
+			return nil, &RuntimeError{Message: "methods() expects a class."}
+		}
+		// Inherited methods are included, once each.
+		var names []string
+		for c := class; c != nil; c = c.superclass {
+			for name := range c.methods {
+				if !slices.Contains(names, name) {
+					names = append(names, name)
+				}
+			}
+		}
+		slices.Sort(names)
+		return stringList(names), nil
+	}})
+
+	i.globals.define("hasField", &NativeFunction{"hasField", 2, 2, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		instance, name, err := fieldArguments("hasField", arguments)
+		if err != nil {
+			return nil, err
+		}
+		_, exists := instance.fields[name]
+		return exists, nil
+	}})
+
+	i.globals.define("getField", &NativeFunction{"getField", 2, 2, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		instance, name, err := fieldArguments("getField", arguments)
+		if err != nil {
+			return nil, err
+		}
+		value, exists := instance.fields[name]
+		if !exists {
+			return nil, &RuntimeError{Message: fmt.Sprintf("Undefined field '%v'.", name)}
+		}
+		return value, nil
+	}})
+
+	i.globals.define("setField", &NativeFunction{"setField", 3, 3, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		instance, name, err := fieldArguments("setField", arguments)
+		if err != nil {
+			return nil, err
+		}
+		instance.fields[name] = arguments[2]
+		return arguments[2], nil
+	}})
+
+	// deleteField returns whether the field existed.
+	i.globals.define("deleteField", &NativeFunction{"deleteField", 2, 2, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		instance, name, err := fieldArguments("deleteField", arguments)
+		if err != nil {
+			return nil, err
+		}
+		_, exists := instance.fields[name]
+		delete(instance.fields, name)
+		return exists, nil
+	}})
+}
+
+func stringList(names []string) *LoxList {
+	elements := make([]interface{}, len(names))
+	for i, name := range names {
+		elements[i] = name
+	}
+	return NewList(elements)
+}
+
+func instanceArgument(function string, value interface{}) (*LoxInstance, *RuntimeError) {
+	instance, ok := value.(*LoxInstance)
+	if !ok {
+		return nil, &RuntimeError{Message: fmt.Sprintf("%v() expects an instance.", function)}
+	}
+	return instance, nil
+}
+
+// fieldArguments checks the instance and field name passed to a field builtin.
+func fieldArguments(function string, arguments []interface{}) (*LoxInstance, string, *RuntimeError) {
+	instance, err := instanceArgument(function, arguments[0])
+	if err != nil {
+		return nil, "", err
+	}
+	name, ok := arguments[1].(string)
+	if !ok {
+		return nil, "", &RuntimeError{Message: fmt.Sprintf("%v() expects a field name string.", function)}
+	}
+	return instance, name, nil
+}
@@ -0,0 +1,45 @@
+package lox
+
+import "testing"
+
+func TestReflection(t *testing.T) {
+	checkScripts(t, []scriptTest{
+		{name: "type", source: `
+class A {}
+print type(1);
+print type("s");
+print type(nil);
+print type(true);
+print type(A());
+print type(A);
+print type(clock);
+print type(List());`, want: "number\nstring\nnil\nbool\ninstance\nclass\nfunction\nlist\n"},
+		{name: "is", source: `
+class A {}
+class B < A {}
+var b = B();
+print b is A;
+print b is B;
+print A() is B;
+print 1 is A;`, want: "true\ntrue\nfalse\nfalse\n"},
+		{name: "fields and methods", source: `
+class A { init() { this.x = 1; } m() {} }
+class B < A { n() {} }
+var b = B();
+print classOf(b) == B;
+print fields(b);
+print methods(B);`, want: "true\n[x]\n[init, m, n]\n"},
+		{name: "field access", source: `
+class A {}
+var a = A();
+setField(a, "y", 2);
+print hasField(a, "y");
+print getField(a, "y");
+deleteField(a, "y");
+print hasField(a, "y");`, want: "true\n2\nfalse\n"},
+		{name: "undefined field", source: `class A {} getField(A(), "zz");`,
+			err: "Undefined field 'zz'."},
+		{name: "is non-class", source: `print 1 is 2;`,
+			err: "Right operand of 'is' must be a class or enum."},
+	})
+}
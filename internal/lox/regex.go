@@ -0,0 +1,206 @@
+package lox
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode/utf8"
+)
+
+// LoxRegex is a compiled regular expression, using Go's RE2 syntax.
+type LoxRegex struct {
+	re *regexp.Regexp
+}
+
+func (r *LoxRegex) toString() string {
+	return "<regex /" + r.re.String() + "/>"
+}
+
+func (r *LoxRegex) get(name Token) (interface{}, *RuntimeError) {
+	switch name.Lexeme {
+	case "pattern":
+		return r.re.String(), nil
+	case "test":
+		return r.method("test", 1, func(s string, arguments []interface{}) (interface{}, *RuntimeError) {
+			return r.re.MatchString(s), nil
+		}), nil
+	case "match":
+		// match returns the first match followed by its groups, or nil.
+		return r.method("match", 1, func(s string, arguments []interface{}) (interface{}, *RuntimeError) {
+			groups := r.re.FindStringSubmatchIndex(s)
+			if groups == nil {
+				return nil, nil
+			}
+			return NewList(submatches(s, groups)), nil
+		}), nil
+	case "findAll":
+		return r.method("findAll", 1, func(s string, arguments []interface{}) (interface{}, *RuntimeError) {
+			return stringList(r.re.FindAllString(s, -1)), nil
+		}), nil
+	case "split":
+		return r.method("split", 1, func(s string, arguments []interface{}) (interface{}, *RuntimeError) {
+			return stringList(r.re.Split(s, -1)), nil
+		}), nil
+	case "replace":
+		// replace substitutes every match with a string, which may refer to
+		// groups as $1, or with the result of calling a function with the match
+		// and then its groups.
+		return r.method("replace", 2, func(s string, arguments []interface{}) (interface{}, *RuntimeError) {
+			if replacement, ok := arguments[1].(string); ok {
+				return r.re.ReplaceAllString(s, replacement), nil
+			}
+			return r.replaceFunc(s, arguments[1])
+		}), nil
+	}
+
+	return nil, &RuntimeError{Token: name, Message: fmt.Sprintf("Undefined property '%v'.", name.Lexeme)}
+}
+
+// method makes a native method whose first argument is the string to search.
+func (r *LoxRegex) method(name string, arity int, fn func(s string, arguments []interface{}) (interface{}, *RuntimeError)) *NativeFunction {
+	return &NativeFunction{name, arity, arity, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		s, err := stringArgument(name, arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		return fn(s, arguments)
+	}}
+}
+
+func (r *LoxRegex) replaceFunc(s string, replacement interface{}) (interface{}, *RuntimeError) {
+	function, ok := replacement.(Callable)
+	if !ok {
+		return nil, &RuntimeError{Message: "replace() expects a string or a function."}
+	}
+	// Only pass as many groups as the function accepts.
+	_, maxArity := function.arity()
+
+	var result strings.Builder
+	last := 0
+	for _, groups := range r.re.FindAllStringSubmatchIndex(s, -1) {
+		arguments := submatches(s, groups)
+		if maxArity != variadic && len(arguments) > maxArity {
+			arguments = arguments[:maxArity]
+		}
+		value, err := callValue(function, Token{}, make([]*Token, len(arguments)), arguments)
+		if err != nil {
+			return nil, err
+		}
+		text, err := stringify(value)
+		if err != nil {
+			return nil, err
+		}
+		result.WriteString(s[last:groups[0]])
+		result.WriteString(text)
+		last = groups[1]
+	}
+	result.WriteString(s[last:])
+	return result.String(), nil
+}
+
+// submatches converts submatch indexes into the matched strings, with nil for
+// groups that didn't take part in the match.
+func submatches(s string, indexes []int) []interface{} {
+	var groups []interface{}
+	for i := 0; i < len(indexes); i += 2 {
+		if indexes[i] < 0 {
+			groups = append(groups, nil)
+		} else {
+			groups = append(groups, s[indexes[i]:indexes[i+1]])
+		}
+	}
+	return groups
+}
+
+// compileRegex compiles a pattern, reporting syntax errors with the position
+// in the pattern where they were found.
+func compileRegex(pattern string) (*LoxRegex, *RuntimeError) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		var syntaxErr *syntax.Error
+		if errors.As(err, &syntaxErr) {
+			position := utf8.RuneCountInString(pattern[:regexErrorOffset(pattern, syntaxErr)])
+			return nil, &RuntimeError{Message: fmt.Sprintf("Invalid regex at position %d: %v: `%v`.", position, syntaxErr.Code, syntaxErr.Expr)}
+		}
+		return nil, &RuntimeError{Message: fmt.Sprintf("Invalid regex: %v.", err)}
+	}
+	return &LoxRegex{re}, nil
+}
+
+// regexErrorOffset returns the byte offset in pattern of the part syntaxErr
+// complains about.
+func regexErrorOffset(pattern string, syntaxErr *syntax.Error) int {
+	if syntaxErr.Expr != pattern {
+		// The parser stops at the first error, so the text it quotes is
+		// normally first found where it stopped.
+		return max(strings.Index(pattern, syntaxErr.Expr), 0)
+	}
+
+	// Errors about parentheses quote the whole pattern, so look for the one
+	// that doesn't match, skipping escapes and character classes.
+	var open []int
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], `\Q`):
+			// Everything up to \E is literal, or to the end if there isn't one.
+			end := strings.Index(pattern[i:], `\E`)
+			if end < 0 {
+				end = len(pattern) - i
+			}
+			i += end + 1
+		case pattern[i] == '\\':
+			i++
+		case pattern[i] == '[':
+			i = classEnd(pattern, i)
+		case pattern[i] == '(':
+			open = append(open, i)
+		case pattern[i] == ')':
+			if len(open) == 0 {
+				return i
+			}
+			open = open[:len(open)-1]
+		}
+	}
+	if len(open) > 0 {
+		return open[len(open)-1]
+	}
+	return 0
+}
+
+// classEnd returns the index of the ']' closing the character class that
+// starts at pattern[start].
+func classEnd(pattern string, start int) int {
+	i := start + 1
+	if strings.HasPrefix(pattern[i:], "^") {
+		i++
+	}
+	if strings.HasPrefix(pattern[i:], "]") {
+		// A ']' right at the start is part of the class.
+		i++
+	}
+	for ; i < len(pattern); i++ {
+		switch {
+		case pattern[i] == '\\':
+			i++
+		case strings.HasPrefix(pattern[i:], "[:"):
+			if end := strings.Index(pattern[i:], ":]"); end >= 0 {
+				i += end + 1
+			}
+		case pattern[i] == ']':
+			return i
+		}
+	}
+	return len(pattern)
+}
+
+func defineRegexNatives(i *Interpreter) {
+	i.globals.define("Regex", &NativeFunction{"Regex", 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		pattern, err := stringArgument("Regex", arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		return compileRegex(pattern)
+	}})
+}
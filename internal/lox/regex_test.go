@@ -0,0 +1,56 @@
+package lox
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRegex(t *testing.T) {
+	checkScripts(t, []scriptTest{
+		{name: "test and match", source: `
+var r = Regex("(\w+)@(\w+)\.com");
+print r.test("bob@ex.com");
+print r.test("nope");
+print r.match("mail bob@ex.com now");
+print r.match("zzz");
+print r.pattern;`, want: "true\nfalse\n[bob@ex.com, bob, ex]\nnil\n(\\w+)@(\\w+)\\.com\n"},
+		{name: "findAll and split", source: `
+print Regex("\w+@\w+\.com").findAll("a@b.com c@d.com");
+print Regex(",\s*").split("a, b,c");`, want: "[a@b.com, c@d.com]\n[a, b, c]\n"},
+		{name: "replace", source: `
+print Regex("\d+").replace("a1b22", "#");
+print Regex("(\w)(\d)").replace("a1 b2", "$2$1");
+print Regex("\d+").replace("a1b22", (m) => m.length);`, want: "a#b#\n1a 2b\na1b2\n"},
+		{name: "invalid pattern", source: `Regex("(");`,
+			err: "missing closing ): `(`."},
+		{name: "bad replacement", source: `Regex("a").replace("a", 1);`,
+			err: "replace() expects a string or a function."},
+	})
+}
+
+func TestRegexErrorPosition(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		position int
+	}{
+		{"abc(def", 3},
+		{"ab)c", 2},
+		{"((a)", 0},
+		{"(a)(b", 3},
+		{`[(]x(`, 4},
+		{`\((`, 2},
+		{`(\Q)\E`, 0},
+		{"a[bc", 1},
+		{"x**", 1},
+		{"é(", 1},
+	}
+
+	for _, test := range tests {
+		_, err := compileRegex(test.pattern)
+		want := fmt.Sprintf("Invalid regex at position %d:", test.position)
+		if err == nil || !strings.HasPrefix(err.Message, want) {
+			t.Errorf("compiling %q: got %v, want %q", test.pattern, err, want)
+		}
+	}
+}
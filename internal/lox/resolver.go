@@ -1,5 +1,11 @@
 package lox
 
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
 type FunctionType int
 
 const (
@@ -7,6 +13,8 @@ const (
 	Funct
 	InitFunc
 	MethodFunc
+	GetterFunc
+	SetterFunc
 )
 
 type ClassType int
@@ -15,6 +23,7 @@ const (
 	NoClass ClassType = iota
 	RegularClass
 	SubClass
+	TraitClass
 )
 
 type LoopType int
@@ -30,6 +39,18 @@ type Resolver struct {
 	currentFunction FunctionType
 	currentLoop     LoopType
 	currentClass    ClassType
+	// traits records the method names of each trait declared in this run, so
+	// conflicting mix-ins can be reported before the class is executed.
+	traits map[string][]string
+	// constants mirrors scopes, marking the names declared with const.
+	constants []map[string]bool
+	// labels holds the labels of the loops enclosing the current statement.
+	labels []string
+	// inAsync is set while resolving the body of an async function.
+	inAsync bool
+	// enums records the members of each enum declared in this run, so switches
+	// over them can be checked for exhaustiveness.
+	enums map[string][]string
 }
 
 type Resolvable interface {
@@ -43,6 +64,8 @@ func NewResolver(i *Interpreter) *Resolver {
 		currentFunction: NoFunct,
 		currentClass:    NoClass,
 		currentLoop:     NoLoop,
+		traits:          make(map[string][]string),
+		enums:           make(map[string][]string),
 	}
 }
 
@@ -58,6 +81,7 @@ func (c *Class) Resolve(r *Resolver) {
 		r.currentClass = enclosingClass
 	}()
 
+	r.resolveExprs(c.decorators)
 	r.declare(c.name)
 	r.define(c.name)
 
@@ -70,11 +94,24 @@ func (c *Class) Resolve(r *Resolver) {
 		}
 	}
 
+	for _, t := range c.traits {
+		t.Resolve(r)
+	}
+	r.checkTraitConflicts(c)
+
 	if c.superclass != nil {
 		r.beginScope()
 		peek(r.scopes)["super"] = true
 	}
 
+	// Method decorators run when the class is declared, outside any method.
+	for _, method := range c.methods {
+		if method.name.Lexeme == "init" && len(method.decorators) > 0 {
+			loxError(*method.name, "Can't decorate an initializer.")
+		}
+		r.resolveExprs(method.decorators)
+	}
+
 	r.beginScope()
 	peek(r.scopes)["this"] = true
 
@@ -85,6 +122,18 @@ func (c *Class) Resolve(r *Resolver) {
 		}
 		r.resolveFunction(*method, ftype)
 	}
+	for _, getter := range c.getters {
+		if getter.name.Lexeme == "init" {
+			loxError(*getter.name, "Can't use 'init' as a getter.")
+		}
+		r.resolveFunction(*getter, GetterFunc)
+	}
+	for _, setter := range c.setters {
+		if setter.name.Lexeme == "init" {
+			loxError(*setter.name, "Can't use 'init' as a setter.")
+		}
+		r.resolveFunction(*setter, SetterFunc)
+	}
 
 	r.endScope()
 
@@ -92,6 +141,97 @@ func (c *Class) Resolve(r *Resolver) {
 		r.endScope()
 	}
 }
+func (r *Resolver) resolveExprs(exprs []Expr) {
+	for _, expr := range exprs {
+		expr.(Resolvable).Resolve(r)
+	}
+}
+func (r *Resolver) checkTraitConflicts(c *Class) {
+	overridden := make(map[string]bool)
+	for _, method := range c.methods {
+		overridden[method.name.Lexeme] = true
+	}
+
+	providers := make(map[string]string)
+	for _, t := range c.traits {
+		for _, name := range r.traits[t.name.Lexeme] {
+			if other, exists := providers[name]; exists && other != t.name.Lexeme && !overridden[name] {
+				loxError(t.name, fmt.Sprintf("Method '%v' from trait '%v' conflicts with trait '%v'.", name, t.name.Lexeme, other))
+			}
+			providers[name] = t.name.Lexeme
+		}
+	}
+}
+func (t *Trait) Resolve(r *Resolver) {
+	enclosingClass := r.currentClass
+	r.currentClass = TraitClass
+	defer func() {
+		r.currentClass = enclosingClass
+	}()
+
+	r.declare(t.name)
+	r.define(t.name)
+
+	var names []string
+	for _, method := range t.methods {
+		names = append(names, method.name.Lexeme)
+	}
+	r.traits[t.name.Lexeme] = names
+
+	// Mirror the 'super' and 'this' scopes a mixed-in method runs under.
+	r.beginScope()
+	peek(r.scopes)["super"] = true
+	r.beginScope()
+	peek(r.scopes)["this"] = true
+
+	for _, method := range t.methods {
+		ftype := MethodFunc
+		if method.name.Lexeme == "init" {
+			ftype = InitFunc
+		}
+		r.resolveFunction(*method, ftype)
+	}
+
+	r.endScope()
+	r.endScope()
+}
+func (e *Enum) Resolve(r *Resolver) {
+	enclosingClass := r.currentClass
+	r.currentClass = RegularClass
+	defer func() {
+		r.currentClass = enclosingClass
+	}()
+
+	r.declare(e.name)
+	r.define(e.name)
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, member := range e.members {
+		if seen[member.Lexeme] {
+			loxError(member, fmt.Sprintf("Duplicate enum member '%v'.", member.Lexeme))
+		}
+		// 'values' is the list of members, so no member can take its name.
+		if member.Lexeme == "values" {
+			loxError(member, "Can't use 'values' as an enum member name.")
+		}
+		seen[member.Lexeme] = true
+		names = append(names, member.Lexeme)
+	}
+	r.enums[e.name.Lexeme] = names
+
+	r.beginScope()
+	peek(r.scopes)["this"] = true
+
+	for _, method := range e.methods {
+		if method.name.Lexeme == "init" {
+			loxError(*method.name, "Can't declare an initializer in an enum.")
+		}
+		r.resolveFunction(*method, MethodFunc)
+	}
+
+	r.endScope()
+}
 func (e *Expression) Resolve(r *Resolver) {
 	e.expression.(Resolvable).Resolve(r)
 }
@@ -101,6 +241,9 @@ func (v *Var) Resolve(r *Resolver) {
 		v.initializer.(Resolvable).Resolve(r)
 	}
 	r.define(v.name)
+	if v.constant && len(r.scopes) != 0 {
+		peek(r.constants)[v.name.Lexeme] = true
+	}
 }
 func (w *While) Resolve(r *Resolver) {
 	enclosingLoop := r.currentLoop
@@ -111,10 +254,89 @@ func (w *While) Resolve(r *Resolver) {
 
 	r.currentLoop = enclosingLoop
 }
+func (d *DoWhile) Resolve(r *Resolver) {
+	enclosingLoop := r.currentLoop
+	r.currentLoop = Loop
+
+	d.body.(Resolvable).Resolve(r)
+	d.condition.(Resolvable).Resolve(r)
+
+	r.currentLoop = enclosingLoop
+}
+func (l *Labeled) Resolve(r *Resolver) {
+	r.labels = append(r.labels, l.label.Lexeme)
+	l.body.(Resolvable).Resolve(r)
+	r.labels = r.labels[:len(r.labels)-1]
+}
+func (s *Switch) Resolve(r *Resolver) {
+	s.value.(Resolvable).Resolve(r)
+	for _, c := range s.cases {
+		for _, value := range c.values {
+			value.(Resolvable).Resolve(r)
+		}
+		c.body.Resolve(r)
+	}
+	if s.defaultBranch != nil {
+		s.defaultBranch.Resolve(r)
+	} else {
+		r.checkExhaustive(s)
+	}
+}
+
+// checkExhaustive reports enum members missing from a switch without a
+// default, when every case is a member of the same enum.
+func (r *Resolver) checkExhaustive(s *Switch) {
+	enum := ""
+	covered := make(map[string]bool)
+	for _, c := range s.cases {
+		for _, value := range c.values {
+			get, ok := value.(*Get)
+			if !ok {
+				return
+			}
+			variable, ok := get.object.(*Variable)
+			if !ok || (enum != "" && variable.name.Lexeme != enum) {
+				return
+			}
+			enum = variable.name.Lexeme
+			covered[get.name.Lexeme] = true
+		}
+	}
+
+	members, ok := r.enums[enum]
+	if !ok {
+		return
+	}
+	var absent []string
+	for _, member := range members {
+		if !covered[member] {
+			absent = append(absent, member)
+		}
+	}
+	if len(absent) > 0 {
+		loxError(s.keyword, fmt.Sprintf("Switch on enum '%v' is missing %v %v.",
+			enum, plural("case", len(absent)), strings.Join(absent, ", ")))
+	}
+}
 func (a *Assign) Resolve(r *Resolver) {
 	a.value.(Resolvable).Resolve(r)
+	for i := len(r.scopes) - 1; i >= 0; i-- {
+		if _, ok := r.scopes[i][a.name.Lexeme]; ok {
+			if r.constants[i][a.name.Lexeme] {
+				loxError(a.name, fmt.Sprintf("Can't assign to constant '%v'.", a.name.Lexeme))
+			}
+			break
+		}
+	}
 	r.resolveLocal(a, a.name)
 }
+func (a *Await) Resolve(r *Resolver) {
+	// Top-level code may await too; it runs the event loop until the promise settles.
+	if r.currentFunction != NoFunct && !r.inAsync {
+		loxError(a.keyword, "Can only use 'await' inside an async function.")
+	}
+	a.value.(Resolvable).Resolve(r)
+}
 func (b *Binary) Resolve(r *Resolver) {
 	b.left.(Resolvable).Resolve(r)
 	b.right.(Resolvable).Resolve(r)
@@ -125,6 +347,12 @@ func (c *Call) Resolve(r *Resolver) {
 		arg.(Resolvable).Resolve(r)
 	}
 }
+func (s *Spawn) Resolve(r *Resolver) {
+	s.call.Resolve(r)
+}
+func (s *Spread) Resolve(r *Resolver) {
+	s.expression.(Resolvable).Resolve(r)
+}
 func (g *Get) Resolve(r *Resolver) {
 	g.object.(Resolvable).Resolve(r)
 }
@@ -138,6 +366,22 @@ func (l *Logical) Resolve(r *Resolver) {
 	l.left.(Resolvable).Resolve(r)
 	l.right.(Resolvable).Resolve(r)
 }
+func (p *Pipe) Resolve(r *Resolver) {
+	p.left.(Resolvable).Resolve(r)
+	p.right.(Resolvable).Resolve(r)
+}
+func (i *Index) Resolve(r *Resolver) {
+	i.object.(Resolvable).Resolve(r)
+	if i.start != nil {
+		i.start.(Resolvable).Resolve(r)
+	}
+	if i.end != nil {
+		i.end.(Resolvable).Resolve(r)
+	}
+}
+func (o *Optional) Resolve(r *Resolver) {
+	o.expression.(Resolvable).Resolve(r)
+}
 func (s *Set) Resolve(r *Resolver) {
 	s.value.(Resolvable).Resolve(r)
 	s.object.(Resolvable).Resolve(r)
@@ -145,7 +389,7 @@ func (s *Set) Resolve(r *Resolver) {
 func (s *Super) Resolve(r *Resolver) {
 	if r.currentClass == NoClass {
 		loxError(s.keyword, "Can't use 'super' outside of a class.")
-	} else if r.currentClass != SubClass {
+	} else if r.currentClass != SubClass && r.currentClass != TraitClass {
 		loxError(s.keyword, "Can't use 'super' in a class with no superclass!")
 	}
 	r.resolveLocal(s, s.keyword)
@@ -160,6 +404,7 @@ func (u *Unary) Resolve(r *Resolver) {
 	u.right.(Resolvable).Resolve(r)
 }
 func (f *Function) Resolve(r *Resolver) {
+	r.resolveExprs(f.decorators)
 	r.declare(*f.name)
 	r.define(*f.name)
 
@@ -176,31 +421,27 @@ func (p *Print) Resolve(r *Resolver) {
 	p.expression.(Resolvable).Resolve(r)
 }
 func (af *AnonFunction) Resolve(r *Resolver) {
-	r.beginScope()
-
-	// Declare and define each parameter within the function's scope
-	for _, param := range af.params {
-		r.declare(param)
-		r.define(param)
-	}
-
-	// Resolve the function body statements
-	r.resolveStatements(af.body)
-
-	// End the scope after resolving the function body
-	r.endScope()
+	r.resolveFunction(*af.function, Funct)
 }
 func (re *Return) Resolve(r *Resolver) {
 	if r.currentFunction == NoFunct {
 		loxError(re.keyword, "Can't return from top-level code.")
 	}
 
-	if re.value != nil {
-		if r.currentFunction == InitFunc {
-			loxError(re.keyword, "Can't return a value from an initializer.")
+	if re.value == nil {
+		if r.currentFunction == GetterFunc {
+			loxError(re.keyword, "A getter must return a value.")
 		}
-		re.value.(Resolvable).Resolve(r)
+		return
+	}
+
+	if r.currentFunction == InitFunc {
+		loxError(re.keyword, "Can't return a value from an initializer.")
+	}
+	if r.currentFunction == SetterFunc {
+		loxError(re.keyword, "Can't return a value from a setter.")
 	}
+	re.value.(Resolvable).Resolve(r)
 }
 func (v *Variable) Resolve(r *Resolver) {
 	if len(r.scopes) != 0 {
@@ -214,22 +455,49 @@ func (v *Variable) Resolve(r *Resolver) {
 }
 func (b *Break) Resolve(r *Resolver) {
 	if r.currentLoop == NoLoop {
-		loxError(Token{Type: BREAK, Lexeme: "break"}, "Can't use 'break' outside of a loop.")
+		loxError(b.keyword, "Can't use 'break' outside of a loop.")
+	}
+	if b.label != nil && !slices.Contains(r.labels, b.label.Lexeme) {
+		loxError(*b.label, fmt.Sprintf("Undefined label '%v'.", b.label.Lexeme))
 	}
 }
 func (r *Resolver) resolveFunction(function Function, ftype FunctionType) {
 	enclosingFunction := r.currentFunction
 	r.currentFunction = ftype
+	// A loop around the declaration doesn't extend into the function body.
+	enclosingLoop, enclosingLabels := r.currentLoop, r.labels
+	r.currentLoop, r.labels = NoLoop, nil
+	enclosingAsync := r.inAsync
+	r.inAsync = function.async
+	if function.async && ftype == InitFunc {
+		loxError(*function.name, "Can't make an initializer async.")
+	}
+
+	// Defaults are evaluated in the closure, outside the function's own scope.
+	r.resolveDefaults(function.defaults)
 
 	r.beginScope()
 	for _, param := range function.params {
 		r.declare(param)
 		r.define(param)
 	}
+	if function.rest != nil {
+		r.declare(*function.rest)
+		r.define(*function.rest)
+	}
 	r.resolveStatements(function.body)
 	r.endScope()
 
 	r.currentFunction = enclosingFunction
+	r.currentLoop, r.labels = enclosingLoop, enclosingLabels
+	r.inAsync = enclosingAsync
+}
+func (r *Resolver) resolveDefaults(defaults []Expr) {
+	for _, value := range defaults {
+		if value != nil {
+			value.(Resolvable).Resolve(r)
+		}
+	}
 }
 func (r *Resolver) resolveStatements(statements []Stmt) {
 	for _, statement := range statements {
@@ -241,11 +509,13 @@ func (r *Resolver) resolveStatements(statements []Stmt) {
 
 func (r *Resolver) beginScope() {
 	r.scopes = append(r.scopes, make(map[string]bool))
+	r.constants = append(r.constants, make(map[string]bool))
 }
 
 func (r *Resolver) endScope() {
 	if len(r.scopes) > 0 {
 		r.scopes = r.scopes[:len(r.scopes)-1]
+		r.constants = r.constants[:len(r.constants)-1]
 	}
 }
 
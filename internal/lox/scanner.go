@@ -4,28 +4,39 @@ import (
 	"errors"
 	"fmt"
 	"log"
-	"os"
 	"strconv"
 )
 
 var keywords = map[string]TokenType{
-	"and":    AND,
-	"break":  BREAK,
-	"class":  CLASS,
-	"else":   ELSE,
-	"false":  FALSE,
-	"for":    FOR,
-	"fun":    FUN,
-	"if":     IF,
-	"nil":    NIL,
-	"or":     OR,
-	"print":  PRINT,
-	"return": RETURN,
-	"super":  SUPER,
-	"this":   THIS,
-	"true":   TRUE,
-	"var":    VAR,
-	"while":  WHILE,
+	"and":     AND,
+	"async":   ASYNC,
+	"await":   AWAIT,
+	"break":   BREAK,
+	"case":    CASE,
+	"class":   CLASS,
+	"const":   CONST,
+	"default": DEFAULT,
+	"do":      DO,
+	"else":    ELSE,
+	"enum":    ENUM,
+	"false":   FALSE,
+	"for":     FOR,
+	"fun":     FUN,
+	"if":      IF,
+	"is":      IS,
+	"nil":     NIL,
+	"or":      OR,
+	"print":   PRINT,
+	"return":  RETURN,
+	"spawn":   SPAWN,
+	"super":   SUPER,
+	"switch":  SWITCH,
+	"this":    THIS,
+	"trait":   TRAIT,
+	"true":    TRUE,
+	"var":     VAR,
+	"while":   WHILE,
+	"with":    WITH,
 }
 
 type Scanner struct {
@@ -86,10 +97,24 @@ func (s *Scanner) scanToken() error {
 		s.addToken(LEFT_BRACE, nil)
 	case '}':
 		s.addToken(RIGHT_BRACE, nil)
+	case '[':
+		s.addToken(LEFT_BRACKET, nil)
+	case ']':
+		s.addToken(RIGHT_BRACKET, nil)
 	case ',':
 		s.addToken(COMMA, nil)
+	case ':':
+		s.addToken(COLON, nil)
+	case '@':
+		s.addToken(AT, nil)
 	case '.':
-		s.addToken(DOT, nil)
+		if s.peek() == '.' && s.peekNext() == '.' {
+			s.advance()
+			s.advance()
+			s.addToken(DOT_DOT_DOT, nil)
+		} else {
+			s.addToken(DOT, nil)
+		}
 	case '-':
 		s.addToken(MINUS, nil)
 	case '+':
@@ -107,6 +132,8 @@ func (s *Scanner) scanToken() error {
 	case '=':
 		if s.match('=') {
 			s.addToken(EQUAL_EQUAL, nil)
+		} else if s.match('>') {
+			s.addToken(ARROW, nil)
 		} else {
 			s.addToken(EQUAL, nil)
 		}
@@ -122,6 +149,22 @@ func (s *Scanner) scanToken() error {
 		} else {
 			s.addToken(GREATER, nil)
 		}
+	case '|':
+		if s.match('>') {
+			s.addToken(PIPE_GREATER, nil)
+		} else {
+			fmt.Fprintf(interpreter.stderr, "[line %d] Error%s:%s\n", s.Line, "", "Unexpected character.")
+			return errors.New("")
+		}
+	case '?':
+		if s.match('.') {
+			s.addToken(QUESTION_DOT, nil)
+		} else if s.match('?') {
+			s.addToken(QUESTION_QUESTION, nil)
+		} else {
+			fmt.Fprintf(interpreter.stderr, "[line %d] Error%s:%s\n", s.Line, "", "Unexpected character.")
+			return errors.New("")
+		}
 	case '/':
 		if s.match('/') {
 			// A comment goes to the end of the line
@@ -156,7 +199,7 @@ func (s *Scanner) scanToken() error {
 		} else if isAlpha(c) {
 			s.handleIdentifier()
 		} else {
-			fmt.Fprintf(os.Stderr, "[line %d] Error%s:%s\n", s.Line, "", "Unexpected character.")
+			fmt.Fprintf(interpreter.stderr, "[line %d] Error%s:%s\n", s.Line, "", "Unexpected character.")
 			return errors.New("")
 		}
 	}
@@ -210,7 +253,7 @@ func (s *Scanner) handleString() error {
 	}
 
 	if s.isAtEnd() {
-		fmt.Fprintf(os.Stderr, "[line %d] Error%s:%s\n", s.Line, "", "Unterminated string.")
+		fmt.Fprintf(interpreter.stderr, "[line %d] Error%s:%s\n", s.Line, "", "Unterminated string.")
 		return errors.New("")
 	}
 
@@ -238,7 +281,7 @@ func (s *Scanner) handleNumber() {
 		}
 	}
 
-	value, err := strconv.ParseFloat(s.Source[s.start:s.Current], 32)
+	value, err := strconv.ParseFloat(s.Source[s.start:s.Current], 64)
 	if err != nil {
 		log.Fatal(err)
 	}
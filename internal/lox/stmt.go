@@ -8,9 +8,24 @@ type Block struct {
   statements []Stmt
 }
 
+type DoWhile struct {
+  body Stmt
+  condition Expr
+}
+
 type Class struct {
   name Token
   superclass *Variable
+  traits []*Variable
+  methods []*Function
+  getters []*Function
+  setters []*Function
+  decorators []Expr
+}
+
+type Enum struct {
+  name Token
+  members []Token
   methods []*Function
 }
 
@@ -21,12 +36,11 @@ type Expression struct {
 type Function struct {
   name *Token
   params []Token
+  defaults []Expr
+  rest *Token
   body []Stmt
-}
-
-type AnonFunction struct {
-  params []Token
-  body []Stmt
+  async bool
+  decorators []Expr
 }
 
 type If struct {
@@ -35,6 +49,16 @@ type If struct {
   elseBranch Stmt
 }
 
+type Labeled struct {
+  label Token
+  body Stmt
+}
+
+type Trait struct {
+  name Token
+  methods []*Function
+}
+
 type Print struct {
   expression Expr
 }
@@ -44,9 +68,17 @@ type Return struct {
   value Expr
 }
 
+type Switch struct {
+  keyword Token
+  value Expr
+  cases []SwitchCase
+  defaultBranch *Block
+}
+
 type Var struct {
   initializer Expr
   name Token
+  constant bool
 }
 
 type While struct {
@@ -55,6 +87,7 @@ type While struct {
 }
 
 type Break struct {
-  
+  keyword Token
+  label *Token
 }
 
@@ -0,0 +1,210 @@
+package lox
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// stringMethod is a method available on every string. fn receives the string
+// it was called on along with the arguments.
+type stringMethod struct {
+	minArity int
+	maxArity int
+	fn       func(s string, arguments []interface{}) (interface{}, *RuntimeError)
+}
+
+// stringMethods are available both as methods, as in s.upper(), and in the
+// strings module, as in strings.upper(s). Positions are counted in runes.
+var stringMethods = map[string]stringMethod{
+	"upper": {0, 0, func(s string, arguments []interface{}) (interface{}, *RuntimeError) {
+		return strings.ToUpper(s), nil
+	}},
+	"lower": {0, 0, func(s string, arguments []interface{}) (interface{}, *RuntimeError) {
+		return strings.ToLower(s), nil
+	}},
+	"trim": {0, 0, func(s string, arguments []interface{}) (interface{}, *RuntimeError) {
+		return strings.TrimSpace(s), nil
+	}},
+	"split": {1, 1, func(s string, arguments []interface{}) (interface{}, *RuntimeError) {
+		separator, err := stringArgument("split", arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		return stringList(strings.Split(s, separator)), nil
+	}},
+	// join joins the elements of a list with the string between them.
+	"join": {1, 1, func(s string, arguments []interface{}) (interface{}, *RuntimeError) {
+		list, ok := arguments[0].(*LoxList)
+		if !ok {
+			return nil, &RuntimeError{Message: "join() expects a list."}
+		}
+		parts := make([]string, len(list.elements))
+		for i, element := range list.elements {
+			text, err := stringify(element)
+			if err != nil {
+				return nil, err
+			}
+			parts[i] = text
+		}
+		return strings.Join(parts, s), nil
+	}},
+	"replace": {2, 2, func(s string, arguments []interface{}) (interface{}, *RuntimeError) {
+		old, err := stringArgument("replace", arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		replacement, err := stringArgument("replace", arguments[1])
+		if err != nil {
+			return nil, err
+		}
+		return strings.ReplaceAll(s, old, replacement), nil
+	}},
+	"startsWith": {1, 1, func(s string, arguments []interface{}) (interface{}, *RuntimeError) {
+		prefix, err := stringArgument("startsWith", arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		return strings.HasPrefix(s, prefix), nil
+	}},
+	"endsWith": {1, 1, func(s string, arguments []interface{}) (interface{}, *RuntimeError) {
+		suffix, err := stringArgument("endsWith", arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		return strings.HasSuffix(s, suffix), nil
+	}},
+	// find returns the position of the first occurrence of a substring, or -1.
+	"find": {1, 1, func(s string, arguments []interface{}) (interface{}, *RuntimeError) {
+		substring, err := stringArgument("find", arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		i := strings.Index(s, substring)
+		if i < 0 {
+			return -1.0, nil
+		}
+		return float64(utf8.RuneCountInString(s[:i])), nil
+	}},
+	"repeat": {1, 1, func(s string, arguments []interface{}) (interface{}, *RuntimeError) {
+		count, ok := arguments[0].(float64)
+		if !ok || count < 0 || count != float64(int(count)) {
+			return nil, &RuntimeError{Message: "repeat() expects a non-negative integer."}
+		}
+		if count > 0 && float64(len(s)) > maxStringLength/count {
+			return nil, &RuntimeError{Message: "repeat() result is too long."}
+		}
+		return strings.Repeat(s, int(count)), nil
+	}},
+	// padLeft pads the string to a width with spaces, or another character.
+	"padLeft": {1, 2, func(s string, arguments []interface{}) (interface{}, *RuntimeError) {
+		width, ok := arguments[0].(float64)
+		if !ok || width != float64(int(width)) {
+			return nil, &RuntimeError{Message: "padLeft() expects an integer width."}
+		}
+		pad := " "
+		if len(arguments) == 2 {
+			p, err := stringArgument("padLeft", arguments[1])
+			if err != nil {
+				return nil, err
+			}
+			if utf8.RuneCountInString(p) != 1 {
+				return nil, &RuntimeError{Message: "padLeft() pad must be a single character."}
+			}
+			pad = p
+		}
+		if width > maxStringLength {
+			return nil, &RuntimeError{Message: "padLeft() width is too large."}
+		}
+		if missing := int(width) - utf8.RuneCountInString(s); missing > 0 {
+			return strings.Repeat(pad, missing) + s, nil
+		}
+		return s, nil
+	}},
+	"chars": {0, 0, func(s string, arguments []interface{}) (interface{}, *RuntimeError) {
+		var chars []string
+		for _, r := range s {
+			chars = append(chars, string(r))
+		}
+		return stringList(chars), nil
+	}},
+}
+
+// maxStringLength bounds the strings repeat() and padLeft() build, in bytes,
+// so a huge count fails instead of exhausting memory.
+const maxStringLength = 1 << 28
+
+// stringProperty reads a property of a string.
+func stringProperty(s string, name Token) (interface{}, *RuntimeError) {
+	if name.Lexeme == "length" {
+		return float64(utf8.RuneCountInString(s)), nil
+	}
+	method, exists := stringMethods[name.Lexeme]
+	if !exists {
+		return nil, &RuntimeError{Token: name, Message: fmt.Sprintf("Undefined property '%v'.", name.Lexeme)}
+	}
+	return &NativeFunction{name.Lexeme, method.minArity, method.maxArity, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		return method.fn(s, arguments)
+	}}, nil
+}
+
+func stringArgument(function string, value interface{}) (string, *RuntimeError) {
+	s, ok := value.(string)
+	if !ok {
+		return "", &RuntimeError{Message: fmt.Sprintf("%v() expects a string.", function)}
+	}
+	return s, nil
+}
+
+// newStringsModule makes the strings module, whose functions take the string
+// as their first argument.
+func newStringsModule() *LoxModule {
+	m := &LoxModule{name: "strings", members: make(map[string]interface{})}
+	for name, method := range stringMethods {
+		name, method := name, method
+		maxArity := method.maxArity
+		if maxArity != variadic {
+			maxArity++
+		}
+		m.define(name, method.minArity+1, maxArity, func(arguments []interface{}) (interface{}, *RuntimeError) {
+			s, err := stringArgument("strings."+name, arguments[0])
+			if err != nil {
+				return nil, err
+			}
+			return method.fn(s, arguments[1:])
+		})
+	}
+	m.define("length", 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		s, err := stringArgument("strings.length", arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		return float64(utf8.RuneCountInString(s)), nil
+	})
+	return m
+}
+
+// defineStringNatives registers the strings module and the character code
+// conversions.
+func defineStringNatives(i *Interpreter) {
+	i.globals.define("strings", newStringsModule())
+
+	// ord returns the code point of a one-character string.
+	i.globals.define("ord", &NativeFunction{"ord", 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		s, ok := arguments[0].(string)
+		if !ok || utf8.RuneCountInString(s) != 1 {
+			return nil, &RuntimeError{Message: "ord() expects a single character."}
+		}
+		r, _ := utf8.DecodeRuneInString(s)
+		return float64(r), nil
+	}})
+
+	// chr returns the one-character string for a code point.
+	i.globals.define("chr", &NativeFunction{"chr", 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		code, ok := arguments[0].(float64)
+		if !ok || code != float64(int(code)) || !utf8.ValidRune(rune(code)) {
+			return nil, &RuntimeError{Message: "chr() expects a valid code point."}
+		}
+		return string(rune(code)), nil
+	}})
+}
@@ -0,0 +1,41 @@
+package lox
+
+import "testing"
+
+func TestStrings(t *testing.T) {
+	checkScripts(t, []scriptTest{
+		{name: "methods", source: `
+var s = "Hello, World";
+print s.upper();
+print s.lower();
+print "  x ".trim();
+print s.split(", ");
+print "-".join(List("a", "b"));
+print s.replace("l", "L");
+print s.startsWith("He");
+print s.endsWith("x");
+print s.find("World");
+print "ab".repeat(3);
+print "7".padLeft(3, "0");`, want: "HELLO, WORLD\nhello, world\nx\n[Hello, World]\na-b\nHeLLo, WorLd\ntrue\nfalse\n7\nababab\n007\n"},
+		{name: "characters", source: `
+print "héllo".length;
+print "héllo".chars();
+print "héllo"[1];
+print ord("A");
+print chr(97);`, want: "5\n[h, é, l, l, o]\né\n65\na\n"},
+		{name: "slices", source: `
+var s = "Hello, World";
+print s[0:5];
+print s[7:];
+print List(1, 2, 3, 4)[1:3];`, want: "Hello\nWorld\n[2, 3]\n"},
+		{name: "module", source: `print strings.upper("a"); print strings.length("héllo");`, want: "A\n5\n"},
+		{name: "index out of range", source: `print "a"[5];`,
+			err: "String index out of range."},
+		{name: "unknown method", source: `"a".nope();`,
+			err: "Undefined property 'nope'."},
+		{name: "repeat too long", source: `"ab".repeat(1000000000000000000);`,
+			err: "repeat() result is too long."},
+		{name: "pad too wide", source: `"x".padLeft(1000000000000000000);`,
+			err: "padLeft() width is too large."},
+	})
+}
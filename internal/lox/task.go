@@ -0,0 +1,186 @@
+package lox
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Tasks started with spawn run on their own goroutines, but they all share the
+// one interpreter: its environments, instance fields and event loop. The memory
+// model is a single interpreter lock. Lox code only runs while holding it, so
+// values are shared between tasks without copying and without races. A task
+// gives the lock up when it blocks on a channel, joins another task, or sleeps,
+// and that is where other tasks get to run.
+
+// taskState is the part of the interpreter that belongs to the running task.
+type taskState struct {
+	environment *Environment
+	frames      []frame
+	coroutine   *coroutine
+}
+
+func (i *Interpreter) saveState() taskState {
+	return taskState{i.environment, i.frames, i.coroutine}
+}
+
+func (i *Interpreter) restoreState(state taskState) {
+	i.environment, i.frames, i.coroutine = state.environment, state.frames, state.coroutine
+}
+
+// unlocked runs fn without holding the interpreter lock, so other tasks can run
+// while it blocks. fn must not touch Lox values. If another task called exit()
+// in the meantime, it returns the error that unwinds this one.
+func (i *Interpreter) unlocked(fn func()) *RuntimeError {
+	state := i.saveState()
+	i.lock.Unlock()
+	fn()
+	i.lock.Lock()
+	i.restoreState(state)
+
+	if i.exited {
+		return exitError()
+	}
+	return nil
+}
+
+// wait blocks the current task until another task calls notify. Callers check
+// their condition in a loop around it. If every task is waiting, only the event
+// loop can wake one, so the last task to wait starts a task that runs the
+// loop's next step. Once the loop has nothing left either, none of them can
+// ever wake the others, so they all fail with a deadlock.
+func (i *Interpreter) wait() *RuntimeError {
+	state := i.saveState()
+	defer i.restoreState(state)
+
+	i.blocked++
+	deadlocks := i.deadlocks
+	if i.blocked == i.running && !i.stepLoop() {
+		i.deadlocks++
+		i.notify()
+	} else {
+		i.changed.Wait()
+	}
+
+	if i.exited {
+		return exitError()
+	}
+	if i.deadlocks != deadlocks {
+		return &RuntimeError{Message: "Deadlock: all tasks are blocked."}
+	}
+	return nil
+}
+
+// stepLoop runs the event loop's next step on a task of its own, so that a
+// callback can block on a channel like any other task. It returns false if the
+// loop has nothing to run.
+func (i *Interpreter) stepLoop() bool {
+	if !i.loop.pending() {
+		return false
+	}
+	i.running++
+
+	go func() {
+		i.lock.Lock()
+		defer i.lock.Unlock()
+		i.restoreState(taskState{environment: i.globals})
+
+		i.loop.step()
+		i.running--
+		i.notify()
+	}()
+	return true
+}
+
+// notify wakes every waiting task. They count as running again until they
+// find they still have to wait.
+func (i *Interpreter) notify() {
+	i.blocked = 0
+	i.changed.Broadcast()
+}
+
+// waitForTasks blocks until every spawned task has finished, then reports the
+// errors of tasks nobody joined.
+func (i *Interpreter) waitForTasks() {
+	for i.running > 1 && !i.exited {
+		// On a deadlock the blocked tasks fail and report it themselves.
+		i.wait()
+	}
+
+	for _, t := range i.tasks {
+		if t.err != nil && !t.joined && !i.exited {
+			i.hadRuntimeError = true
+			fmt.Fprintf(i.stderr, "Unhandled error in spawned task: %s\n", t.err.Error())
+			if len(t.err.trace) > 0 {
+				fmt.Fprintln(i.stderr, strings.Join(t.err.trace, "\n"))
+			}
+		}
+	}
+	i.tasks = nil
+}
+
+// LoxTask is the handle returned by spawn.
+type LoxTask struct {
+	finished bool
+	joined   bool
+	value    interface{}
+	err      *RuntimeError
+}
+
+func (t *LoxTask) toString() string {
+	if t.finished {
+		return "<task finished>"
+	}
+	return "<task running>"
+}
+
+func (t *LoxTask) get(name Token) (interface{}, *RuntimeError) {
+	if name.Lexeme == "join" {
+		return &NativeFunction{"join", 0, 0, func(arguments []interface{}) (interface{}, *RuntimeError) {
+			return t.join()
+		}}, nil
+	}
+	return nil, &RuntimeError{Token: name, Message: fmt.Sprintf("Undefined property '%v'.", name.Lexeme)}
+}
+
+// join waits for the task to finish and returns its result. An error in the
+// task is raised again in the joining task.
+func (t *LoxTask) join() (interface{}, *RuntimeError) {
+	for !t.finished {
+		if err := interpreter.wait(); err != nil {
+			return nil, err
+		}
+	}
+	t.joined = true
+	return t.value, t.err
+}
+
+// Eval calls the function on a new task. The callee and arguments are
+// evaluated first, by the spawning task.
+func (s *Spawn) Eval() (interface{}, *RuntimeError) {
+	callee, err := s.call.callee.Eval()
+	if err != nil {
+		return nil, err
+	}
+	names, arguments, err := s.call.evaluateArguments()
+	if err != nil {
+		return nil, err
+	}
+
+	i := interpreter
+	t := &LoxTask{}
+	state := taskState{i.environment, append([]frame{}, i.frames...), nil}
+	i.tasks = append(i.tasks, t)
+	i.running++
+
+	go func() {
+		i.lock.Lock()
+		defer i.lock.Unlock()
+		i.restoreState(state)
+
+		t.value, t.err = callValue(callee, s.call.paren, names, arguments)
+		t.finished = true
+		i.running--
+		i.notify()
+	}()
+	return t, nil
+}
@@ -0,0 +1,80 @@
+package lox
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSpawn(t *testing.T) {
+	checkScripts(t, []scriptTest{
+		{name: "join", source: `
+fun add(a, b) { return a + b; }
+var t = spawn add(1, 2);
+print t.join();
+print t;`, want: "3\n<task finished>\n"},
+		{name: "join error", source: `
+fun f() { return nil.x; }
+var t = spawn f();
+t.join();`, err: "Only instances have properties."},
+		{name: "unjoined error", source: `
+fun f() { return nil.x; }
+spawn f();
+print "main";`, want: "main\n", err: "Unhandled error in spawned task: Only instances have properties."},
+	})
+}
+
+func TestUnjoinedErrorStatus(t *testing.T) {
+	_, stderr, status := runScript(t, `fun f() { return nil.x; } spawn f();`)
+	if !strings.Contains(stderr, "Unhandled error in spawned task") || status != 70 {
+		t.Errorf("got error output %q and status %d, want an unhandled task error and status 70", stderr, status)
+	}
+}
+
+func TestChannels(t *testing.T) {
+	checkScripts(t, []scriptTest{
+		{name: "producer", source: `
+var ch = Channel();
+fun produce(n) { for (var i = 0; i < n; i = i + 1) ch.send(i); ch.close(); }
+spawn produce(3);
+var v = ch.receive();
+while (v != nil) { print v; v = ch.receive(); }`, want: "0\n1\n2\n"},
+		{name: "buffered", source: `
+var c = Channel(2);
+c.send(1);
+c.send(2);
+print c;
+c.close();
+print c.receive();
+print c.receive();
+print c.receive() == nil;`, want: "<channel 2/2>\n1\n2\ntrue\n"},
+		{name: "select", source: `
+var a = Channel(1);
+var b = Channel(1);
+b.send(2);
+print select(a, b).get(1);`, want: "2\n"},
+		{name: "send on closed", source: `var c = Channel(1); c.close(); c.send(1);`,
+			err: "Send on closed channel."},
+		{name: "timer wakes main", source: `
+var ch = Channel(1);
+setTimeout(() => { ch.send("x"); }, 10);
+print ch.receive();`, want: "x\n"},
+		{name: "timer sends unbuffered", source: `
+var ch = Channel();
+setTimeout(() => { ch.send("x"); print "sent"; }, 10);
+print ch.receive();`, want: "x\nsent\n"},
+		{name: "timer wakes task", source: `
+var ch = Channel();
+fun wait() { print ch.receive(); }
+spawn wait();
+setTimeout(() => { ch.send("late"); }, 10);
+print "main";`, want: "main\nlate\n"},
+		{name: "deadlock", source: `var ch = Channel(); print ch.receive();`,
+			err: "Deadlock: all tasks are blocked."},
+		{name: "deadlock between tasks", source: `
+var a = Channel();
+var b = Channel();
+fun f() { a.receive(); b.send(1); }
+spawn f();
+b.receive();`, err: "Deadlock: all tasks are blocked."},
+	})
+}
@@ -0,0 +1,204 @@
+package lox
+
+import (
+	"fmt"
+	"time"
+)
+
+// LoxDate is a point in time in a particular time zone.
+type LoxDate struct {
+	t time.Time
+}
+
+func (d *LoxDate) toString() string {
+	return d.t.Format("2006-01-02T15:04:05.000Z07:00")
+}
+
+func (d *LoxDate) get(name Token) (interface{}, *RuntimeError) {
+	switch name.Lexeme {
+	case "year":
+		return float64(d.t.Year()), nil
+	case "month":
+		return float64(d.t.Month()), nil
+	case "day":
+		return float64(d.t.Day()), nil
+	case "hour":
+		return float64(d.t.Hour()), nil
+	case "minute":
+		return float64(d.t.Minute()), nil
+	case "second":
+		return float64(d.t.Second()), nil
+	case "millisecond":
+		return float64(d.t.Nanosecond() / int(time.Millisecond)), nil
+	case "weekday":
+		// Sunday is 0.
+		return float64(d.t.Weekday()), nil
+	case "yearDay":
+		return float64(d.t.YearDay()), nil
+	case "zone":
+		name, _ := d.t.Zone()
+		return name, nil
+	case "offset":
+		// Seconds east of UTC.
+		_, offset := d.t.Zone()
+		return float64(offset), nil
+	case "unix":
+		return float64(d.t.UnixMilli()) / 1000.0, nil
+	case "format":
+		return &NativeFunction{"format", 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+			layout, err := stringArgument("format", arguments[0])
+			if err != nil {
+				return nil, err
+			}
+			return d.t.Format(layout), nil
+		}}, nil
+	case "in":
+		// in returns the same instant in another time zone.
+		return &NativeFunction{"in", 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+			location, err := locationArgument("in", arguments[0])
+			if err != nil {
+				return nil, err
+			}
+			return &LoxDate{d.t.In(location)}, nil
+		}}, nil
+	case "add":
+		// add returns the date a number of milliseconds later.
+		return &NativeFunction{"add", 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+			ms, ok := arguments[0].(float64)
+			if !ok {
+				return nil, &RuntimeError{Message: "add() expects a number of milliseconds."}
+			}
+			return &LoxDate{d.t.Add(milliseconds(ms))}, nil
+		}}, nil
+	case "sub":
+		// sub returns the milliseconds from another date to this one.
+		return &NativeFunction{"sub", 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+			other, ok := arguments[0].(*LoxDate)
+			if !ok {
+				return nil, &RuntimeError{Message: "sub() expects a date."}
+			}
+			return float64(d.t.Sub(other.t)) / float64(time.Millisecond), nil
+		}}, nil
+	case "equals":
+		return &NativeFunction{"equals", 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+			other, ok := arguments[0].(*LoxDate)
+			return ok && d.t.Equal(other.t), nil
+		}}, nil
+	}
+
+	return nil, &RuntimeError{Token: name, Message: fmt.Sprintf("Undefined property '%v'.", name.Lexeme)}
+}
+
+// locationArgument loads a time zone by its IANA name, such as
+// "Europe/Paris", or "UTC" or "Local".
+func locationArgument(function string, value interface{}) (*time.Location, *RuntimeError) {
+	name, err := stringArgument(function, value)
+	if err != nil {
+		return nil, err
+	}
+	location, loadErr := time.LoadLocation(name)
+	if loadErr != nil {
+		return nil, &RuntimeError{Message: fmt.Sprintf("%v(): unknown time zone '%v'.", function, name)}
+	}
+	return location, nil
+}
+
+// newTimeModule makes the time module. Layouts for formatting and parsing
+// are written as Go's reference time, Mon Jan 2 15:04:05 MST 2006, and
+// durations are numbers of milliseconds.
+func newTimeModule(i *Interpreter) *LoxModule {
+	m := &LoxModule{name: "time", members: map[string]interface{}{
+		"RFC3339":  time.RFC3339,
+		"DATE":     time.DateOnly,
+		"DATETIME": time.DateTime,
+		"TIME":     time.TimeOnly,
+	}}
+
+	m.define("now", 0, 0, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		return &LoxDate{i.clock.Now()}, nil
+	})
+	// monotonic returns the seconds since the interpreter started, which
+	// never go backwards, for measuring elapsed time.
+	m.define("monotonic", 0, 0, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		return i.clock.Now().Sub(i.started).Seconds(), nil
+	})
+	m.define("unix", 1, 2, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		seconds, ok := arguments[0].(float64)
+		if !ok {
+			return nil, &RuntimeError{Message: "time.unix() expects a number of seconds."}
+		}
+		t := time.UnixMilli(int64(seconds * 1000))
+		if len(arguments) == 2 {
+			location, err := locationArgument("time.unix", arguments[1])
+			if err != nil {
+				return nil, err
+			}
+			t = t.In(location)
+		}
+		return &LoxDate{t}, nil
+	})
+	// date builds a date from its components, with the time of day and zone
+	// optional.
+	m.define("date", 3, 7, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		location := time.Local
+		if len(arguments) == 7 {
+			var err *RuntimeError
+			if location, err = locationArgument("time.date", arguments[6]); err != nil {
+				return nil, err
+			}
+			arguments = arguments[:6]
+		}
+		parts, err := numberArguments("time.date", arguments)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, make([]float64, 6-len(parts))...)
+		return &LoxDate{time.Date(int(parts[0]), time.Month(parts[1]), int(parts[2]),
+			int(parts[3]), int(parts[4]), int(parts[5]), 0, location)}, nil
+	})
+	// parse reads a date written in layout, in the given zone if the text
+	// doesn't name one.
+	m.define("parse", 2, 3, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		layout, err := stringArgument("time.parse", arguments[0])
+		if err != nil {
+			return nil, err
+		}
+		text, err := stringArgument("time.parse", arguments[1])
+		if err != nil {
+			return nil, err
+		}
+		location := time.Local
+		if len(arguments) == 3 {
+			if location, err = locationArgument("time.parse", arguments[2]); err != nil {
+				return nil, err
+			}
+		}
+		t, parseErr := time.ParseInLocation(layout, text, location)
+		if parseErr != nil {
+			return nil, &RuntimeError{Message: fmt.Sprintf("time.parse(): %v.", parseErr)}
+		}
+		return &LoxDate{t}, nil
+	})
+	// sleep blocks for a number of milliseconds, letting spawned tasks run.
+	m.define("sleep", 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+		ms, ok := arguments[0].(float64)
+		if !ok {
+			return nil, &RuntimeError{Message: "time.sleep() expects a number of milliseconds."}
+		}
+		return nil, i.unlocked(func() { i.clock.Sleep(milliseconds(ms)) })
+	})
+
+	units := map[string]time.Duration{"seconds": time.Second, "minutes": time.Minute, "hours": time.Hour, "days": 24 * time.Hour}
+	for name, unit := range units {
+		name, unit := name, unit
+		m.define(name, 1, 1, func(arguments []interface{}) (interface{}, *RuntimeError) {
+			n, ok := arguments[0].(float64)
+			if !ok {
+				return nil, &RuntimeError{Message: fmt.Sprintf("time.%v() expects a number.", name)}
+			}
+			return n * float64(unit/time.Millisecond), nil
+		})
+	}
+
+	return m
+}
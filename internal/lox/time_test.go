@@ -0,0 +1,68 @@
+package lox
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTime(t *testing.T) {
+	checkScripts(t, []scriptTest{
+		{name: "components", source: `
+var d = time.date(2024, 2, 29, 13, 5, 9, "UTC");
+print d;
+print d.year;
+print d.month;
+print d.day;
+print d.weekday;
+print d.yearDay;
+print d.zone;`, want: "2024-02-29T13:05:09.000Z\n2024\n2\n29\n4\n60\nUTC\n"},
+		{name: "format and arithmetic", source: `
+var d = time.date(2024, 2, 29, 13, 5, 9, "UTC");
+print d.format(time.DATE);
+print d.add(time.days(1)).format(time.DATETIME);
+print time.parse(time.DATETIME, "2024-03-01 13:05:09", "UTC").sub(d) / time.hours(1);
+print d.equals(time.unix(d.unix, "UTC"));
+print time.unix(0, "UTC");`, want: "2024-02-29\n2024-03-01 13:05:09\n24\ntrue\n1970-01-01T00:00:00.000Z\n"},
+		{name: "parse error", source: `time.parse(time.DATE, "nope");`,
+			err: `time.parse(): parsing time "nope" as "2006-01-02": cannot parse "nope" as "2006".`},
+		{name: "unknown zone", source: `time.date(2024, 1, 1, 0, 0, 0, "Mars/Base");`,
+			err: "time.date(): unknown time zone 'Mars/Base'."},
+	})
+}
+
+func TestClock(t *testing.T) {
+	source := `
+setTimeout(() => { print time.now().in("UTC"); }, 1500);
+print time.now().in("UTC");
+time.sleep(250);
+print time.monotonic() * 1000;
+`
+	stdout, stderr, _ := runScript(t, source, clockEnv+"=2024-01-02T03:04:05Z")
+	want := "2024-01-02T03:04:05.000Z\n250\n2024-01-02T03:04:06.500Z\n"
+	if stdout != want || stderr != "" {
+		t.Errorf("got %q, stderr %q; want %q", stdout, stderr, want)
+	}
+}
+
+func TestClockBelongsToInterpreter(t *testing.T) {
+	i := NewInterpreter()
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	i.SetClock(&fakeClock{now: now})
+
+	clock, err := i.globals.get(Token{Lexeme: "clock"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	seconds, err := clock.(Callable).call(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := float64(now.Unix()); seconds != want {
+		t.Errorf("clock() = %v, want %v", seconds, want)
+	}
+
+	i.loop.schedule(time.Second, false, func() {})
+	if due := i.loop.nextTimer().due; !due.Equal(now.Add(time.Second)) {
+		t.Errorf("timer due at %v, want %v", due, now.Add(time.Second))
+	}
+}
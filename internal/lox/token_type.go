@@ -8,8 +8,13 @@ const (
 	RIGHT_PAREN
 	LEFT_BRACE
 	RIGHT_BRACE
+	LEFT_BRACKET
+	RIGHT_BRACKET
 	COMMA
+	COLON
+	AT
 	DOT
+	DOT_DOT_DOT
 	MINUS
 	PLUS
 	SEMICOLON
@@ -21,10 +26,14 @@ const (
 	BANG_EQUAL
 	EQUAL
 	EQUAL_EQUAL
+	ARROW
 	GREATER
 	GREATER_EQUAL
 	LESS
 	LESS_EQUAL
+	QUESTION_DOT
+	QUESTION_QUESTION
+	PIPE_GREATER
 
 	// Literals.
 	IDENTIFIER
@@ -33,65 +42,98 @@ const (
 
 	// Keywords.
 	AND
+	ASYNC
+	AWAIT
 	BREAK
+	CASE
 	CLASS
+	CONST
+	DEFAULT
+	DO
 	ELSE
+	ENUM
 	FALSE
 	FUN
 	FOR
 	IF
+	IS
 	NIL
 	OR
 	PRINT
 	RETURN
+	SPAWN
 	SUPER
+	SWITCH
 	THIS
+	TRAIT
 	TRUE
 	VAR
 	WHILE
+	WITH
 
 	EOF
 )
 
 var tokenNames = map[TokenType]string{
-	LEFT_PAREN:    "LEFT_PAREN",
-	RIGHT_PAREN:   "RIGHT_PAREN",
-	LEFT_BRACE:    "LEFT_BRACE",
-	RIGHT_BRACE:   "RIGHT_BRACE",
-	COMMA:         "COMMA",
-	DOT:           "DOT",
-	MINUS:         "MINUS",
-	PLUS:          "PLUS",
-	SEMICOLON:     "SEMICOLON",
-	SLASH:         "SLASH",
-	STAR:          "STAR",
-	BANG:          "BANG",
-	BANG_EQUAL:    "BANG_EQUAL",
-	EQUAL:         "EQUAL",
-	EQUAL_EQUAL:   "EQUAL_EQUAL",
-	GREATER:       "GREATER",
-	GREATER_EQUAL: "GREATER_EQUAL",
-	LESS:          "LESS",
-	LESS_EQUAL:    "LESS_EQUAL",
-	IDENTIFIER:    "IDENTIFIER",
-	STRING:        "STRING",
-	NUMBER:        "NUMBER",
-	AND:           "AND",
-	BREAK:         "BREAK",
-	CLASS:         "CLASS",
-	ELSE:          "ELSE",
-	FALSE:         "FALSE",
-	FUN:           "FUN",
-	FOR:           "FOR",
-	IF:            "IF",
-	NIL:           "NIL",
-	OR:            "OR",
-	PRINT:         "PRINT",
-	RETURN:        "RETURN",
-	SUPER:         "SUPER",
-	THIS:          "THIS",
-	TRUE:          "TRUE",
-	VAR:           "VAR",
-	WHILE:         "WHILE",
-	EOF:           "EOF",
+	LEFT_PAREN:        "LEFT_PAREN",
+	RIGHT_PAREN:       "RIGHT_PAREN",
+	LEFT_BRACE:        "LEFT_BRACE",
+	RIGHT_BRACE:       "RIGHT_BRACE",
+	LEFT_BRACKET:      "LEFT_BRACKET",
+	RIGHT_BRACKET:     "RIGHT_BRACKET",
+	COMMA:             "COMMA",
+	COLON:             "COLON",
+	AT:                "AT",
+	DOT:               "DOT",
+	DOT_DOT_DOT:       "DOT_DOT_DOT",
+	MINUS:             "MINUS",
+	PLUS:              "PLUS",
+	SEMICOLON:         "SEMICOLON",
+	SLASH:             "SLASH",
+	STAR:              "STAR",
+	BANG:              "BANG",
+	BANG_EQUAL:        "BANG_EQUAL",
+	EQUAL:             "EQUAL",
+	EQUAL_EQUAL:       "EQUAL_EQUAL",
+	ARROW:             "ARROW",
+	GREATER:           "GREATER",
+	GREATER_EQUAL:     "GREATER_EQUAL",
+	LESS:              "LESS",
+	LESS_EQUAL:        "LESS_EQUAL",
+	QUESTION_DOT:      "QUESTION_DOT",
+	QUESTION_QUESTION: "QUESTION_QUESTION",
+	PIPE_GREATER:      "PIPE_GREATER",
+	IDENTIFIER:        "IDENTIFIER",
+	STRING:            "STRING",
+	NUMBER:            "NUMBER",
+	AND:               "AND",
+	ASYNC:             "ASYNC",
+	AWAIT:             "AWAIT",
+	BREAK:             "BREAK",
+	CASE:              "CASE",
+	CLASS:             "CLASS",
+	CONST:             "CONST",
+	DEFAULT:           "DEFAULT",
+	DO:                "DO",
+	ELSE:              "ELSE",
+	ENUM:              "ENUM",
+	FALSE:             "FALSE",
+	FUN:               "FUN",
+	FOR:               "FOR",
+	IF:                "IF",
+	IS:                "IS",
+	NIL:               "NIL",
+	OR:                "OR",
+	PRINT:             "PRINT",
+	RETURN:            "RETURN",
+	SPAWN:             "SPAWN",
+	SUPER:             "SUPER",
+	SWITCH:            "SWITCH",
+	THIS:              "THIS",
+	TRAIT:             "TRAIT",
+	TRUE:              "TRUE",
+	VAR:               "VAR",
+	WHILE:             "WHILE",
+	WITH:              "WITH",
+	EOF:               "EOF",
 }
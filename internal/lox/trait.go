@@ -0,0 +1,14 @@
+package lox
+
+// LoxTrait is a named bundle of methods that classes mix in with `with`.
+type LoxTrait struct {
+	name    string
+	methods []*Function
+	// closure is the environment the trait was declared in. Mixed-in methods
+	// close over it rather than over the class that uses them.
+	closure *Environment
+}
+
+func (lt *LoxTrait) toString() string {
+	return "<trait " + lt.name + ">"
+}
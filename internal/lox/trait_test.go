@@ -0,0 +1,30 @@
+package lox
+
+import "testing"
+
+func TestTraits(t *testing.T) {
+	checkScripts(t, []scriptTest{
+		{name: "mix in", source: `
+trait Greets { greet() { return "hi " + this.name; } }
+trait Walks { walk() { return this.name + " walks"; } }
+class P with Greets, Walks { init(n) { this.name = n; } }
+var p = P("al");
+print p.greet();
+print p.walk();`, want: "hi al\nal walks\n"},
+		{name: "class method wins", source: `
+trait Greets { greet() { return "trait"; } }
+class P with Greets { greet() { return "class"; } }
+print P().greet();`, want: "class\n"},
+		{name: "trait shadows superclass", source: `
+class Base { who() { return "base"; } }
+trait T { who() { return "trait"; } }
+class D < Base with T {}
+print D().who();`, want: "trait\n"},
+		{name: "conflict", source: `
+trait A { f() {} }
+trait B { f() {} }
+class C with A, B {}`, err: "Method 'f' from trait 'B' conflicts with trait 'A'."},
+		{name: "not a trait", source: `var x = 1; class C with x {}`,
+			err: "Can only mix in traits."},
+	})
+}
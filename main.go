@@ -1,19 +1,47 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/braheezy/gravlax/internal/lox"
 )
 
 func main() {
-	if len(os.Args) > 2 {
-		fmt.Println("Usage: gravlax [filename]")
+	var allowFS []string
+	flag.Func("allow-fs", "let scripts access files under `dir` (repeatable)", func(dirs string) error {
+		for _, dir := range strings.Split(dirs, string(filepath.ListSeparator)) {
+			// An empty entry would otherwise allow the current directory.
+			if dir != "" {
+				allowFS = append(allowFS, dir)
+			}
+		}
+		return nil
+	})
+	seed := flag.Int64("seed", 0, "seed the random module, to make runs reproducible")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "Usage: gravlax [flags] [filename [args...]]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := lox.DefaultInterpreter().AllowFS(allowFS...); err != nil {
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(64)
-	} else if len(os.Args) == 2 {
-		lox.RunFile(os.Args[1])
-	} else {
-		lox.RunPrompt()
 	}
+
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			lox.DefaultInterpreter().Seed(*seed)
+		}
+	})
+
+	if flag.NArg() >= 1 {
+		lox.DefaultInterpreter().SetArgs(flag.Args()[1:])
+		os.Exit(lox.RunFile(flag.Arg(0)))
+	}
+	os.Exit(lox.RunPrompt())
 }
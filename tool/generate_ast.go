@@ -17,14 +17,21 @@ func main() {
 	outputDir := os.Args[1]
 
 	err := defineAst(outputDir, "Expr", []string{
+		"AnonFunction : function *Function",
 		"Assign   : name Token, value Expr",
+		"Await    : keyword Token, value Expr",
 		"Binary   : left Expr, operator Token, right Expr",
-		"Call     : callee Expr, paren Token, arguments []Expr",
-		"Get      : object Expr, name Token",
+		"Call     : callee Expr, paren Token, arguments []Expr, names []*Token",
+		"Get      : object Expr, name Token, optional bool",
 		"Grouping : expression Expr",
+		"Index    : object Expr, bracket Token, start Expr, end Expr, slice bool",
 		"Literal  : value interface{}",
 		"Logical  : left Expr, operator Token, right Expr",
+		"Optional : expression Expr",
+		"Pipe     : left Expr, operator Token, right Expr",
 		"Set      : object Expr, name Token, value Expr",
+		"Spawn    : keyword Token, call *Call",
+		"Spread   : operator Token, expression Expr",
 		"Super    : keyword Token, method Token",
 		"This     : keyword Token",
 		"Unary    : operator Token, right Expr",
@@ -35,16 +42,20 @@ func main() {
 	}
 	err = defineAst(outputDir, "Stmt", []string{
 		"Block        : statements []Stmt",
-		"Class        : name Token, superclass *Variable, methods []*Function",
+		"DoWhile      : body Stmt, condition Expr",
+		"Class        : name Token, superclass *Variable, traits []*Variable, methods []*Function, getters []*Function, setters []*Function, decorators []Expr",
+		"Enum         : name Token, members []Token, methods []*Function",
 		"Expression   : expression Expr",
-		"Function     : name *Token, params []Token, body []Stmt",
-		"AnonFunction : params []Token, body []Stmt",
+		"Function     : name *Token, params []Token, defaults []Expr, rest *Token, body []Stmt, async bool, decorators []Expr",
 		"If           : condition Expr, thenBranch Stmt, elseBranch Stmt",
+		"Labeled      : label Token, body Stmt",
+		"Trait        : name Token, methods []*Function",
 		"Print        : expression Expr",
 		"Return       : keyword Token, value Expr",
-		"Var          : initializer Expr, name Token",
+		"Switch       : keyword Token, value Expr, cases []SwitchCase, defaultBranch *Block",
+		"Var          : initializer Expr, name Token, constant bool",
 		"While        : condition Expr, body Stmt",
-		"Break        : ",
+		"Break        : keyword Token, label *Token",
 	}, "Execute", "*RuntimeError")
 	if err != nil {
 		log.Fatal(err)